@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/pug-go/pug-template/pkg/auth"
+)
+
+// pug-swagger adds security of methods from pug auth options to the
+// swagger spec made by protoc-gen-openapiv2, it's run by make generate:
+//
+//	buf build -o api.binpb
+//	go run ./cmd/pug-swagger -image api.binpb -swagger swagger.json
+func main() {
+	image := flag.String("image", "", "buf image or file descriptor set of the api, with imports")
+	swagger := flag.String("swagger", "swagger.json", "swagger spec, it's rewritten")
+	defaultPolicy := flag.String("default-policy", auth.Policy_Authenticated, "auth policy of methods without one, as in the server config")
+	flag.Parse()
+
+	data, err := os.ReadFile(*image)
+	if err != nil {
+		log.Fatal(err)
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	if err = proto.Unmarshal(data, set); err != nil {
+		log.Fatalf("invalid image %s: %s", *image, err)
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		log.Fatalf("invalid image %s: %s", *image, err)
+	}
+
+	spec, err := os.ReadFile(*swagger)
+	if err != nil {
+		log.Fatal(err)
+	}
+	spec, err = auth.Swagger(spec, files, *defaultPolicy)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = os.WriteFile(*swagger, spec, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
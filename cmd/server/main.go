@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"time"
 
@@ -9,7 +11,13 @@ import (
 	"github.com/pug-go/pug-template/internal/config"
 	"github.com/pug-go/pug-template/internal/handler"
 	"github.com/pug-go/pug-template/internal/server"
+	"github.com/pug-go/pug-template/pkg/accesslog"
+	"github.com/pug-go/pug-template/pkg/auth"
+	"github.com/pug-go/pug-template/pkg/closer"
+	"github.com/pug-go/pug-template/pkg/i18n"
+	"github.com/pug-go/pug-template/pkg/promlib"
 	"github.com/pug-go/pug-template/pkg/pug"
+	"github.com/pug-go/pug-template/pkg/tlsconf"
 )
 
 var flagconf string
@@ -25,18 +33,38 @@ func main() {
 	log.SetReportCaller(true)
 	time.Local = time.UTC
 
-	cfg := &config.GlobalConfig
+	cfg := &config.Config{}
 	err := cfg.Load(flagconf)
 	if err != nil {
 		panic(err)
 	}
+	config.GlobalConfig = *cfg
+	i18n.Default.SetDefaultLocale(cfg.Service.DefaultLocale)
 
-	handlers := handler.New()
-	grpcServer, err := server.NewGrpcServer(handlers.RegisterGrpcServices)
+	grpcTLS, err := tlsconf.New(tlsconf.Config(cfg.Service.TLS.Grpc))
+	if err != nil {
+		panic(err)
+	}
+	httpTLS, err := tlsconf.New(tlsconf.Config(cfg.Service.TLS.Http))
 	if err != nil {
 		panic(err)
 	}
-	httpServer, err := server.NewHttpServer(handlers.InitHttpRoutes)
+	debugTLS, err := tlsconf.New(tlsconf.Config(cfg.Service.TLS.Debug))
+	if err != nil {
+		panic(err)
+	}
+	if !cfg.Service.SinglePort && httpTLS.RequestsClientCert() && grpcTLS == nil {
+		// the gateway connection to grpc must be authenticated, otherwise
+		// forwarded client certificates could be forged
+		panic("http client auth requires grpc tls or single port mode")
+	}
+	peerTLS := grpcTLS
+	if cfg.Service.SinglePort {
+		// grpc is served by the http listener
+		grpcTLS, peerTLS = nil, httpTLS
+	}
+
+	verifier, err := auth.NewVerifier(auth.Config(cfg.Service.Auth))
 	if err != nil {
 		panic(err)
 	}
@@ -44,13 +72,68 @@ func main() {
 	app, err := pug.NewApp(pug.Config{
 		ServiceName: cfg.Service.Name,
 		Domain:      cfg.Service.Domain,
-		GrpcPort:    cfg.Service.Ports.Grpc,
-		HttpPort:    cfg.Service.Ports.Http,
-		DebugPort:   cfg.Service.Ports.Debug,
+		GrpcListen:  config.ListenSpec(cfg.Service.Listen.Grpc, cfg.Service.Ports.Grpc),
+		HttpListen:  config.ListenSpec(cfg.Service.Listen.Http, cfg.Service.Ports.Http),
+		DebugListen: config.ListenSpec(cfg.Service.Listen.Debug, cfg.Service.Ports.Debug),
+		SinglePort:  cfg.Service.SinglePort,
+		HttpTLS:     httpTLS,
+		DebugTLS:    debugTLS,
 	})
 	if err != nil {
 		panic(err)
 	}
+	// deprecated promlib globals are served by /metrics of the app too
+	promlib.SetDefault(app.Metrics())
 
-	app.Run(grpcServer, httpServer)
+	// register your resources and health checks here, e.g.
+	// app.AddResource("postgres", db.Close, db.PingContext, healthcheck.WithInterval(5*time.Second))
+	// and warm-up tasks to run before the public servers accept traffic, e.g.
+	// app.AddWarmup("cache", cache.Load)
+	// and validation messages of custom rules or other locales, e.g.
+	// i18n.Set(i18n.Locale_En, "pug.name.reserved", "name {{.FieldValue}} is reserved")
+	// and outbound clients passing the request id, e.g.
+	// &http.Client{Transport: requestid.Transport(nil)} or
+	// grpc.WithChainUnaryInterceptor(interceptor.UnaryClientRequestId())
+
+	accessLog := accesslog.New(accesslog.Config(cfg.Service.AccessLog))
+	// liveness fails if requests are stuck longer than the http write timeout
+	requests := app.Watchdog().WatchActivity("requests", 2*time.Minute)
+
+	handlers := handler.New()
+	grpcServer, err := server.NewGrpcServer(handlers.RegisterGrpcServices, server.GrpcOptions{
+		Inflight:  app.Inflight(),
+		TLS:       grpcTLS,
+		PeerTLS:   peerTLS,
+		Health:    app.GrpcHealth(),
+		Metrics:   app.Metrics(),
+		AccessLog: accessLog,
+		Verifier:  verifier,
+		Requests:  requests,
+	})
+	if err != nil {
+		panic(err)
+	}
+	httpServer, err := server.NewHttpServer(handlers.InitHttpRoutes, server.HttpOptions{
+		TLS:       httpTLS,
+		GrpcTLS:   grpcTLS,
+		Metrics:   app.Metrics(),
+		AccessLog: accessLog,
+		Requests:  requests,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	ctx, stop := pug.SignalContext(context.Background())
+	pug.UpgradeOnSignal(ctx, app)
+	err = app.Run(ctx, grpcServer, httpServer)
+	stop()
+
+	// resources added to the global closer belong to the process, not the app
+	closeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	err = errors.Join(err, closer.CloseAll(closeCtx))
+	cancel()
+	if err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,296 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.5
+// 	protoc        (unknown)
+// source: pug/errors/v1/errors.proto
+
+package errorsv1pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Violations is the detail of InvalidArgument errors returned by the
+// validation interceptor, the http gateway returns it as "violations".
+type Violations struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Violations    []*Violation           `protobuf:"bytes,1,rep,name=violations,proto3" json:"violations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Violations) Reset() {
+	*x = Violations{}
+	mi := &file_pug_errors_v1_errors_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Violations) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Violations) ProtoMessage() {}
+
+func (x *Violations) ProtoReflect() protoreflect.Message {
+	mi := &file_pug_errors_v1_errors_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Violations.ProtoReflect.Descriptor instead.
+func (*Violations) Descriptor() ([]byte, []int) {
+	return file_pug_errors_v1_errors_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Violations) GetViolations() []*Violation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+// Violation is a failed validation rule of a field.
+type Violation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// field is the full path, e.g. address.lines[2] or labels["env"], empty
+	// for rules of the request message itself.
+	Field string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// rule_id is the failed rule, e.g. string.min_len or a custom CEL rule id.
+	RuleId string `protobuf:"bytes,2,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	// rule_value is the parameter of the rule, e.g. 3 for string.min_len.
+	RuleValue *structpb.Value `protobuf:"bytes,3,opt,name=rule_value,json=ruleValue,proto3" json:"rule_value,omitempty"`
+	// message is the text localized for the caller.
+	Message string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	// for_key is set if the rule failed on a map key, not its value.
+	ForKey        bool `protobuf:"varint,5,opt,name=for_key,json=forKey,proto3" json:"for_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Violation) Reset() {
+	*x = Violation{}
+	mi := &file_pug_errors_v1_errors_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Violation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Violation) ProtoMessage() {}
+
+func (x *Violation) ProtoReflect() protoreflect.Message {
+	mi := &file_pug_errors_v1_errors_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Violation.ProtoReflect.Descriptor instead.
+func (*Violation) Descriptor() ([]byte, []int) {
+	return file_pug_errors_v1_errors_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Violation) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *Violation) GetRuleId() string {
+	if x != nil {
+		return x.RuleId
+	}
+	return ""
+}
+
+func (x *Violation) GetRuleValue() *structpb.Value {
+	if x != nil {
+		return x.RuleValue
+	}
+	return nil
+}
+
+func (x *Violation) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Violation) GetForKey() bool {
+	if x != nil {
+		return x.ForKey
+	}
+	return false
+}
+
+// Denial is the detail of PermissionDenied errors returned by the
+// authorization interceptor, the http gateway returns its fields in the
+// error body.
+type Denial struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// reason is one of MISSING_ROLE, MISSING_SCOPE, MISSING_PERMISSION or
+	// IDENTITY_NOT_ALLOWED.
+	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	// required are the roles, scopes or permissions the caller is missing.
+	Required      []string `protobuf:"bytes,2,rep,name=required,proto3" json:"required,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Denial) Reset() {
+	*x = Denial{}
+	mi := &file_pug_errors_v1_errors_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Denial) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Denial) ProtoMessage() {}
+
+func (x *Denial) ProtoReflect() protoreflect.Message {
+	mi := &file_pug_errors_v1_errors_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Denial.ProtoReflect.Descriptor instead.
+func (*Denial) Descriptor() ([]byte, []int) {
+	return file_pug_errors_v1_errors_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Denial) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Denial) GetRequired() []string {
+	if x != nil {
+		return x.Required
+	}
+	return nil
+}
+
+var File_pug_errors_v1_errors_proto protoreflect.FileDescriptor
+
+var file_pug_errors_v1_errors_proto_rawDesc = string([]byte{
+	0x0a, 0x1a, 0x70, 0x75, 0x67, 0x2f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x2f, 0x76, 0x31, 0x2f,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x70, 0x75,
+	0x67, 0x2e, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x2e, 0x76, 0x31, 0x1a, 0x1c, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72,
+	0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x46, 0x0a, 0x0a, 0x56, 0x69, 0x6f,
+	0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x38, 0x0a, 0x0a, 0x76, 0x69, 0x6f, 0x6c, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x75,
+	0x67, 0x2e, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x69, 0x6f, 0x6c,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x76, 0x69, 0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x22, 0xa4, 0x01, 0x0a, 0x09, 0x56, 0x69, 0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x14, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x72, 0x75, 0x6c, 0x65, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x75, 0x6c, 0x65, 0x49, 0x64, 0x12, 0x35,
+	0x0a, 0x0a, 0x72, 0x75, 0x6c, 0x65, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x09, 0x72, 0x75, 0x6c, 0x65,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x17, 0x0a, 0x07, 0x66, 0x6f, 0x72, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x06, 0x66, 0x6f, 0x72, 0x4b, 0x65, 0x79, 0x22, 0x3c, 0x0a, 0x06, 0x44, 0x65, 0x6e, 0x69,
+	0x61, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65,
+	0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65,
+	0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x42, 0x3d, 0x5a, 0x3b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x70, 0x75, 0x67, 0x2d, 0x67, 0x6f, 0x2f, 0x70, 0x75, 0x67, 0x2d,
+	0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x75, 0x67,
+	0x2f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x2f, 0x76, 0x31, 0x3b, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x73, 0x76, 0x31, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var (
+	file_pug_errors_v1_errors_proto_rawDescOnce sync.Once
+	file_pug_errors_v1_errors_proto_rawDescData []byte
+)
+
+func file_pug_errors_v1_errors_proto_rawDescGZIP() []byte {
+	file_pug_errors_v1_errors_proto_rawDescOnce.Do(func() {
+		file_pug_errors_v1_errors_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pug_errors_v1_errors_proto_rawDesc), len(file_pug_errors_v1_errors_proto_rawDesc)))
+	})
+	return file_pug_errors_v1_errors_proto_rawDescData
+}
+
+var file_pug_errors_v1_errors_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_pug_errors_v1_errors_proto_goTypes = []any{
+	(*Violations)(nil),     // 0: pug.errors.v1.Violations
+	(*Violation)(nil),      // 1: pug.errors.v1.Violation
+	(*Denial)(nil),         // 2: pug.errors.v1.Denial
+	(*structpb.Value)(nil), // 3: google.protobuf.Value
+}
+var file_pug_errors_v1_errors_proto_depIdxs = []int32{
+	1, // 0: pug.errors.v1.Violations.violations:type_name -> pug.errors.v1.Violation
+	3, // 1: pug.errors.v1.Violation.rule_value:type_name -> google.protobuf.Value
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_pug_errors_v1_errors_proto_init() }
+func file_pug_errors_v1_errors_proto_init() {
+	if File_pug_errors_v1_errors_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pug_errors_v1_errors_proto_rawDesc), len(file_pug_errors_v1_errors_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pug_errors_v1_errors_proto_goTypes,
+		DependencyIndexes: file_pug_errors_v1_errors_proto_depIdxs,
+		MessageInfos:      file_pug_errors_v1_errors_proto_msgTypes,
+	}.Build()
+	File_pug_errors_v1_errors_proto = out.File
+	file_pug_errors_v1_errors_proto_goTypes = nil
+	file_pug_errors_v1_errors_proto_depIdxs = nil
+}
@@ -0,0 +1,679 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.5
+// 	protoc        (unknown)
+// source: pug/options/v1/options.proto
+
+package optionsv1pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AuthPolicy is who may call a method when auth is enabled.
+type AuthPolicy int32
+
+const (
+	// AUTH_POLICY_UNSPECIFIED falls back to the service policy, then to the
+	// default policy of the server config.
+	AuthPolicy_AUTH_POLICY_UNSPECIFIED AuthPolicy = 0
+	// AUTH_POLICY_PUBLIC allows anyone, claims of a valid token are still
+	// put on the context.
+	AuthPolicy_AUTH_POLICY_PUBLIC AuthPolicy = 1
+	// AUTH_POLICY_AUTHENTICATED requires a valid bearer token.
+	AuthPolicy_AUTH_POLICY_AUTHENTICATED AuthPolicy = 2
+	// AUTH_POLICY_INTERNAL allows only other services authenticated by a
+	// client certificate, tokens are not enough.
+	AuthPolicy_AUTH_POLICY_INTERNAL AuthPolicy = 3
+)
+
+// Enum value maps for AuthPolicy.
+var (
+	AuthPolicy_name = map[int32]string{
+		0: "AUTH_POLICY_UNSPECIFIED",
+		1: "AUTH_POLICY_PUBLIC",
+		2: "AUTH_POLICY_AUTHENTICATED",
+		3: "AUTH_POLICY_INTERNAL",
+	}
+	AuthPolicy_value = map[string]int32{
+		"AUTH_POLICY_UNSPECIFIED":   0,
+		"AUTH_POLICY_PUBLIC":        1,
+		"AUTH_POLICY_AUTHENTICATED": 2,
+		"AUTH_POLICY_INTERNAL":      3,
+	}
+)
+
+func (x AuthPolicy) Enum() *AuthPolicy {
+	p := new(AuthPolicy)
+	*p = x
+	return p
+}
+
+func (x AuthPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AuthPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_pug_options_v1_options_proto_enumTypes[0].Descriptor()
+}
+
+func (AuthPolicy) Type() protoreflect.EnumType {
+	return &file_pug_options_v1_options_proto_enumTypes[0]
+}
+
+func (x AuthPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AuthPolicy.Descriptor instead.
+func (AuthPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_pug_options_v1_options_proto_rawDescGZIP(), []int{0}
+}
+
+// Field are pug options of a field:
+//
+//	string name = 1 [
+//	  (buf.validate.field).string.min_len = 3,
+//	  (pug.options.v1.field) = {
+//	    messages: [
+//	      {rule_id: "string.min_len", locale: "ru", text: "имя слишком короткое"},
+//	      {rule_id: "string.min_len", locale: "en", text: "name is too short"}
+//	    ]
+//	  }
+//	];
+type Field struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// messages replace the catalog templates of violations of the field rules.
+	Messages      []*ValidationMessage `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Field) Reset() {
+	*x = Field{}
+	mi := &file_pug_options_v1_options_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Field) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Field) ProtoMessage() {}
+
+func (x *Field) ProtoReflect() protoreflect.Message {
+	mi := &file_pug_options_v1_options_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Field.ProtoReflect.Descriptor instead.
+func (*Field) Descriptor() ([]byte, []int) {
+	return file_pug_options_v1_options_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Field) GetMessages() []*ValidationMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+// Message are pug options of a message.
+type Message struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// messages replace the catalog templates of violations of the message
+	// rules, e.g. (buf.validate.message).cel, and of its fields rules when
+	// the field has no own message.
+	Messages      []*ValidationMessage `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	mi := &file_pug_options_v1_options_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_pug_options_v1_options_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_pug_options_v1_options_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Message) GetMessages() []*ValidationMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+// ValidationMessage is a custom text of a violated rule.
+type ValidationMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// rule_id is the violated rule, e.g. string.min_len or a custom CEL rule id.
+	RuleId string `protobuf:"bytes,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	// locale of the text, e.g. en or ru-RU, empty matches any locale.
+	Locale string `protobuf:"bytes,2,opt,name=locale,proto3" json:"locale,omitempty"`
+	// text is a text/template with FieldName, FieldPath, RuleValue and
+	// FieldValue.
+	Text          string `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidationMessage) Reset() {
+	*x = ValidationMessage{}
+	mi := &file_pug_options_v1_options_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidationMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidationMessage) ProtoMessage() {}
+
+func (x *ValidationMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_pug_options_v1_options_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidationMessage.ProtoReflect.Descriptor instead.
+func (*ValidationMessage) Descriptor() ([]byte, []int) {
+	return file_pug_options_v1_options_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ValidationMessage) GetRuleId() string {
+	if x != nil {
+		return x.RuleId
+	}
+	return ""
+}
+
+func (x *ValidationMessage) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *ValidationMessage) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// Service are pug options of a service:
+//
+//	service PugService {
+//	  option (pug.options.v1.service) = {strict_json: true};
+//	  ...
+//	}
+type Service struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// strict_json makes the gateway reject request bodies with unknown
+	// fields, duplicate keys and mistyped values instead of ignoring them.
+	StrictJson bool `protobuf:"varint,1,opt,name=strict_json,json=strictJson,proto3" json:"strict_json,omitempty"`
+	// auth is the policy of methods without own one.
+	Auth          AuthPolicy `protobuf:"varint,2,opt,name=auth,proto3,enum=pug.options.v1.AuthPolicy" json:"auth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Service) Reset() {
+	*x = Service{}
+	mi := &file_pug_options_v1_options_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Service) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Service) ProtoMessage() {}
+
+func (x *Service) ProtoReflect() protoreflect.Message {
+	mi := &file_pug_options_v1_options_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Service.ProtoReflect.Descriptor instead.
+func (*Service) Descriptor() ([]byte, []int) {
+	return file_pug_options_v1_options_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Service) GetStrictJson() bool {
+	if x != nil {
+		return x.StrictJson
+	}
+	return false
+}
+
+func (x *Service) GetAuth() AuthPolicy {
+	if x != nil {
+		return x.Auth
+	}
+	return AuthPolicy_AUTH_POLICY_UNSPECIFIED
+}
+
+// Method are pug options of a method, they override the service options:
+//
+//	rpc HelloPug(HelloPugRequest) returns (HelloPugResponse) {
+//	  option (pug.options.v1.method) = {auth: AUTH_POLICY_PUBLIC};
+//	}
+type Method struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// strict_json overrides Service.strict_json for the method.
+	StrictJson *bool `protobuf:"varint,1,opt,name=strict_json,json=strictJson,proto3,oneof" json:"strict_json,omitempty"`
+	// auth overrides Service.auth for the method.
+	Auth AuthPolicy `protobuf:"varint,2,opt,name=auth,proto3,enum=pug.options.v1.AuthPolicy" json:"auth,omitempty"`
+	// authorization is what the caller must be granted in addition to the
+	// auth policy.
+	Authorization *Authorization `protobuf:"bytes,3,opt,name=authorization,proto3" json:"authorization,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Method) Reset() {
+	*x = Method{}
+	mi := &file_pug_options_v1_options_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Method) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Method) ProtoMessage() {}
+
+func (x *Method) ProtoReflect() protoreflect.Message {
+	mi := &file_pug_options_v1_options_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Method.ProtoReflect.Descriptor instead.
+func (*Method) Descriptor() ([]byte, []int) {
+	return file_pug_options_v1_options_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Method) GetStrictJson() bool {
+	if x != nil && x.StrictJson != nil {
+		return *x.StrictJson
+	}
+	return false
+}
+
+func (x *Method) GetAuth() AuthPolicy {
+	if x != nil {
+		return x.Auth
+	}
+	return AuthPolicy_AUTH_POLICY_UNSPECIFIED
+}
+
+func (x *Method) GetAuthorization() *Authorization {
+	if x != nil {
+		return x.Authorization
+	}
+	return nil
+}
+
+// Authorization of a method is checked against the token claims or the
+// client certificate of the caller when auth is enabled:
+//
+//	option (pug.options.v1.method) = {
+//	  authorization: {roles: ["admin", "support"], scopes: ["pugs:write"]}
+//	};
+type Authorization struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// roles of the roles claim, the caller must have any of them.
+	Roles []string `protobuf:"bytes,1,rep,name=roles,proto3" json:"roles,omitempty"`
+	// scopes of the scope claim, the caller must have all of them.
+	Scopes []string `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// permissions of the permissions claim, the caller must have all of them.
+	Permissions []string `protobuf:"bytes,3,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	// identities of client certificates allowed without claims: a common
+	// name, a DNS name or an URI, e.g. a SPIFFE ID. Only certificates verified
+	// against the client CA have identities, see client_auth of tls.
+	Identities    []string `protobuf:"bytes,4,rep,name=identities,proto3" json:"identities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Authorization) Reset() {
+	*x = Authorization{}
+	mi := &file_pug_options_v1_options_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Authorization) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Authorization) ProtoMessage() {}
+
+func (x *Authorization) ProtoReflect() protoreflect.Message {
+	mi := &file_pug_options_v1_options_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Authorization.ProtoReflect.Descriptor instead.
+func (*Authorization) Descriptor() ([]byte, []int) {
+	return file_pug_options_v1_options_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Authorization) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *Authorization) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *Authorization) GetPermissions() []string {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+func (x *Authorization) GetIdentities() []string {
+	if x != nil {
+		return x.Identities
+	}
+	return nil
+}
+
+var file_pug_options_v1_options_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*Field)(nil),
+		Field:         50001,
+		Name:          "pug.options.v1.field",
+		Tag:           "bytes,50001,opt,name=field",
+		Filename:      "pug/options/v1/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*Message)(nil),
+		Field:         50001,
+		Name:          "pug.options.v1.message",
+		Tag:           "bytes,50001,opt,name=message",
+		Filename:      "pug/options/v1/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.ServiceOptions)(nil),
+		ExtensionType: (*Service)(nil),
+		Field:         50001,
+		Name:          "pug.options.v1.service",
+		Tag:           "bytes,50001,opt,name=service",
+		Filename:      "pug/options/v1/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*Method)(nil),
+		Field:         50001,
+		Name:          "pug.options.v1.method",
+		Tag:           "bytes,50001,opt,name=method",
+		Filename:      "pug/options/v1/options.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// optional pug.options.v1.Field field = 50001;
+	E_Field = &file_pug_options_v1_options_proto_extTypes[0]
+)
+
+// Extension fields to descriptorpb.MessageOptions.
+var (
+	// optional pug.options.v1.Message message = 50001;
+	E_Message = &file_pug_options_v1_options_proto_extTypes[1]
+)
+
+// Extension fields to descriptorpb.ServiceOptions.
+var (
+	// optional pug.options.v1.Service service = 50001;
+	E_Service = &file_pug_options_v1_options_proto_extTypes[2]
+)
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// optional pug.options.v1.Method method = 50001;
+	E_Method = &file_pug_options_v1_options_proto_extTypes[3]
+)
+
+var File_pug_options_v1_options_proto protoreflect.FileDescriptor
+
+var file_pug_options_v1_options_proto_rawDesc = string([]byte{
+	0x0a, 0x1c, 0x70, 0x75, 0x67, 0x2f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x76, 0x31,
+	0x2f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0e,
+	0x70, 0x75, 0x67, 0x2e, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x76, 0x31, 0x1a, 0x20,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
+	0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x22, 0x46, 0x0a, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x3d, 0x0a, 0x08, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x75,
+	0x67, 0x2e, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x08,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x22, 0x48, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x3d, 0x0a, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x75, 0x67, 0x2e, 0x6f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x73, 0x22, 0x58, 0x0a, 0x11, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x72, 0x75, 0x6c, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x75, 0x6c, 0x65, 0x49, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x22, 0x5a, 0x0a, 0x07,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x74, 0x72, 0x69, 0x63,
+	0x74, 0x5f, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x73, 0x74,
+	0x72, 0x69, 0x63, 0x74, 0x4a, 0x73, 0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x04, 0x61, 0x75, 0x74, 0x68,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x70, 0x75, 0x67, 0x2e, 0x6f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x52, 0x04, 0x61, 0x75, 0x74, 0x68, 0x22, 0xb3, 0x01, 0x0a, 0x06, 0x4d, 0x65, 0x74,
+	0x68, 0x6f, 0x64, 0x12, 0x24, 0x0a, 0x0b, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x5f, 0x6a, 0x73,
+	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x0a, 0x73, 0x74, 0x72, 0x69,
+	0x63, 0x74, 0x4a, 0x73, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x2e, 0x0a, 0x04, 0x61, 0x75, 0x74,
+	0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x70, 0x75, 0x67, 0x2e, 0x6f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x50, 0x6f, 0x6c,
+	0x69, 0x63, 0x79, 0x52, 0x04, 0x61, 0x75, 0x74, 0x68, 0x12, 0x43, 0x0a, 0x0d, 0x61, 0x75, 0x74,
+	0x68, 0x6f, 0x72, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1d, 0x2e, 0x70, 0x75, 0x67, 0x2e, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x0d, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x0e,
+	0x0a, 0x0c, 0x5f, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x5f, 0x6a, 0x73, 0x6f, 0x6e, 0x22, 0x7f,
+	0x0a, 0x0d, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x14, 0x0a, 0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05,
+	0x72, 0x6f, 0x6c, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x12, 0x20, 0x0a,
+	0x0b, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0b, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12,
+	0x1e, 0x0a, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x2a,
+	0x7a, 0x0a, 0x0a, 0x41, 0x75, 0x74, 0x68, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x1b, 0x0a,
+	0x17, 0x41, 0x55, 0x54, 0x48, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59, 0x5f, 0x55, 0x4e, 0x53,
+	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x16, 0x0a, 0x12, 0x41, 0x55,
+	0x54, 0x48, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59, 0x5f, 0x50, 0x55, 0x42, 0x4c, 0x49, 0x43,
+	0x10, 0x01, 0x12, 0x1d, 0x0a, 0x19, 0x41, 0x55, 0x54, 0x48, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43,
+	0x59, 0x5f, 0x41, 0x55, 0x54, 0x48, 0x45, 0x4e, 0x54, 0x49, 0x43, 0x41, 0x54, 0x45, 0x44, 0x10,
+	0x02, 0x12, 0x18, 0x0a, 0x14, 0x41, 0x55, 0x54, 0x48, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59,
+	0x5f, 0x49, 0x4e, 0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c, 0x10, 0x03, 0x3a, 0x4c, 0x0a, 0x05, 0x66,
+	0x69, 0x65, 0x6c, 0x64, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0xd1, 0x86, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x75,
+	0x67, 0x2e, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x3a, 0x54, 0x0a, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd1, 0x86, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x70, 0x75, 0x67, 0x2e, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x3a,
+	0x54, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd1, 0x86, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x75, 0x67, 0x2e, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x07, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x3a, 0x50, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12,
+	0x1e, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0xd1, 0x86, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x75, 0x67, 0x2e, 0x6f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x52,
+	0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x42, 0x3f, 0x5a, 0x3d, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x70, 0x75, 0x67, 0x2d, 0x67, 0x6f, 0x2f, 0x70, 0x75, 0x67,
+	0x2d, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x75,
+	0x67, 0x2f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x76, 0x31, 0x3b, 0x6f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x76, 0x31, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var (
+	file_pug_options_v1_options_proto_rawDescOnce sync.Once
+	file_pug_options_v1_options_proto_rawDescData []byte
+)
+
+func file_pug_options_v1_options_proto_rawDescGZIP() []byte {
+	file_pug_options_v1_options_proto_rawDescOnce.Do(func() {
+		file_pug_options_v1_options_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pug_options_v1_options_proto_rawDesc), len(file_pug_options_v1_options_proto_rawDesc)))
+	})
+	return file_pug_options_v1_options_proto_rawDescData
+}
+
+var file_pug_options_v1_options_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pug_options_v1_options_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_pug_options_v1_options_proto_goTypes = []any{
+	(AuthPolicy)(0),                     // 0: pug.options.v1.AuthPolicy
+	(*Field)(nil),                       // 1: pug.options.v1.Field
+	(*Message)(nil),                     // 2: pug.options.v1.Message
+	(*ValidationMessage)(nil),           // 3: pug.options.v1.ValidationMessage
+	(*Service)(nil),                     // 4: pug.options.v1.Service
+	(*Method)(nil),                      // 5: pug.options.v1.Method
+	(*Authorization)(nil),               // 6: pug.options.v1.Authorization
+	(*descriptorpb.FieldOptions)(nil),   // 7: google.protobuf.FieldOptions
+	(*descriptorpb.MessageOptions)(nil), // 8: google.protobuf.MessageOptions
+	(*descriptorpb.ServiceOptions)(nil), // 9: google.protobuf.ServiceOptions
+	(*descriptorpb.MethodOptions)(nil),  // 10: google.protobuf.MethodOptions
+}
+var file_pug_options_v1_options_proto_depIdxs = []int32{
+	3,  // 0: pug.options.v1.Field.messages:type_name -> pug.options.v1.ValidationMessage
+	3,  // 1: pug.options.v1.Message.messages:type_name -> pug.options.v1.ValidationMessage
+	0,  // 2: pug.options.v1.Service.auth:type_name -> pug.options.v1.AuthPolicy
+	0,  // 3: pug.options.v1.Method.auth:type_name -> pug.options.v1.AuthPolicy
+	6,  // 4: pug.options.v1.Method.authorization:type_name -> pug.options.v1.Authorization
+	7,  // 5: pug.options.v1.field:extendee -> google.protobuf.FieldOptions
+	8,  // 6: pug.options.v1.message:extendee -> google.protobuf.MessageOptions
+	9,  // 7: pug.options.v1.service:extendee -> google.protobuf.ServiceOptions
+	10, // 8: pug.options.v1.method:extendee -> google.protobuf.MethodOptions
+	1,  // 9: pug.options.v1.field:type_name -> pug.options.v1.Field
+	2,  // 10: pug.options.v1.message:type_name -> pug.options.v1.Message
+	4,  // 11: pug.options.v1.service:type_name -> pug.options.v1.Service
+	5,  // 12: pug.options.v1.method:type_name -> pug.options.v1.Method
+	13, // [13:13] is the sub-list for method output_type
+	13, // [13:13] is the sub-list for method input_type
+	9,  // [9:13] is the sub-list for extension type_name
+	5,  // [5:9] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_pug_options_v1_options_proto_init() }
+func file_pug_options_v1_options_proto_init() {
+	if File_pug_options_v1_options_proto != nil {
+		return
+	}
+	file_pug_options_v1_options_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pug_options_v1_options_proto_rawDesc), len(file_pug_options_v1_options_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   6,
+			NumExtensions: 4,
+			NumServices:   0,
+		},
+		GoTypes:           file_pug_options_v1_options_proto_goTypes,
+		DependencyIndexes: file_pug_options_v1_options_proto_depIdxs,
+		EnumInfos:         file_pug_options_v1_options_proto_enumTypes,
+		MessageInfos:      file_pug_options_v1_options_proto_msgTypes,
+		ExtensionInfos:    file_pug_options_v1_options_proto_extTypes,
+	}.Build()
+	File_pug_options_v1_options_proto = out.File
+	file_pug_options_v1_options_proto_goTypes = nil
+	file_pug_options_v1_options_proto_depIdxs = nil
+}
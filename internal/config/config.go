@@ -1,6 +1,9 @@
 package config
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
@@ -9,15 +12,86 @@ type Config struct {
 		Name   string `yaml:"name" env:"SERVICE_NAME"`
 		Domain string `yaml:"domain" env:"DOMAIN"`
 		Ports  struct {
-			Grpc  int16 `yaml:"grpc" env:"GRPC_PORT" env-default:"8080"`
-			Http  int16 `yaml:"http" env:"HTTP_PORT" env-default:"8082"`
-			Debug int16 `yaml:"debug" env:"DEBUG_PORT" env-default:"8084"`
+			Grpc  int `yaml:"grpc" env:"GRPC_PORT" env-default:"8080"`
+			Http  int `yaml:"http" env:"HTTP_PORT" env-default:"8082"`
+			Debug int `yaml:"debug" env:"DEBUG_PORT" env-default:"8084"`
 		} `yaml:"ports"`
+		// Listen overrides ports with listener specs:
+		// tcp://127.0.0.1:8080, unix:///run/pug.sock or fd://3
+		Listen struct {
+			Grpc  string `yaml:"grpc" env:"GRPC_LISTEN"`
+			Http  string `yaml:"http" env:"HTTP_LISTEN"`
+			Debug string `yaml:"debug" env:"DEBUG_LISTEN"`
+		} `yaml:"listen"`
+		// SinglePort serves grpc and http on the http port
+		SinglePort bool `yaml:"single_port" env:"SINGLE_PORT" env-default:"false"`
+		// DefaultLocale of messages when the client locale isn't supported
+		DefaultLocale string `yaml:"default_locale" env:"DEFAULT_LOCALE" env-default:"ru"`
+		TLS           struct {
+			Grpc  TLS `yaml:"grpc" env-prefix:"GRPC_TLS_"`
+			Http  TLS `yaml:"http" env-prefix:"HTTP_TLS_"`
+			Debug TLS `yaml:"debug" env-prefix:"DEBUG_TLS_"`
+		} `yaml:"tls"`
+		AccessLog AccessLog `yaml:"access_log" env-prefix:"ACCESS_LOG_"`
+		Auth      Auth      `yaml:"auth" env-prefix:"AUTH_"`
 	} `yaml:"service"`
 }
 
+// TLS of a listener, disabled if cert file is empty. ClientAuth is one of:
+// none, request, require, verify_if_given, require_and_verify. Certificates
+// of request and require aren't verified, so callers get no identity from
+// them, e.g. for the internal auth policy. Client auth of http requires grpc
+// tls unless single port mode is on, so that client certificates are
+// forwarded to grpc over an authenticated connection.
+type TLS struct {
+	CertFile     string `yaml:"cert_file" env:"CERT_FILE"`
+	KeyFile      string `yaml:"key_file" env:"KEY_FILE"`
+	ClientCAFile string `yaml:"client_ca_file" env:"CLIENT_CA_FILE"`
+	ClientAuth   string `yaml:"client_auth" env:"CLIENT_AUTH"`
+}
+
+// AccessLog of grpc calls and http requests. SampleRate from 0 to 1 applies
+// to successful requests, failed ones are always logged. PayloadMethods are
+// grpc methods, e.g. /pug.v1.PugService/HelloPug, or services, e.g.
+// pug.v1.PugService, which messages are logged with fields marked by the
+// debug_redact option redacted.
+type AccessLog struct {
+	Enabled        bool     `yaml:"enabled" env:"ENABLED" env-default:"true"`
+	SampleRate     float64  `yaml:"sample_rate" env:"SAMPLE_RATE" env-default:"1"`
+	PayloadMethods []string `yaml:"payload_methods" env:"PAYLOAD_METHODS" env-separator:","`
+	MaxPayloadSize int      `yaml:"max_payload_size" env:"MAX_PAYLOAD_SIZE" env-default:"4096"`
+}
+
+// Auth of grpc calls with bearer JWTs verified by keys of JwksFile, or of
+// JwksUrl if the file is empty. Issuer and Audience are required when auth
+// is enabled, a token must have the issuer and one of the audiences.
+// DefaultPolicy is one of public, authenticated or internal, it applies to
+// methods without the auth option. The authorization option is enforced
+// even if auth is disabled, then methods requiring claims are denied.
+type Auth struct {
+	Enabled       bool          `yaml:"enabled" env:"ENABLED" env-default:"false"`
+	JwksFile      string        `yaml:"jwks_file" env:"JWKS_FILE"`
+	JwksUrl       string        `yaml:"jwks_url" env:"JWKS_URL"`
+	JwksRefresh   time.Duration `yaml:"jwks_refresh" env:"JWKS_REFRESH" env-default:"5m"`
+	Issuer        string        `yaml:"issuer" env:"ISSUER"`
+	Audience      []string      `yaml:"audience" env:"AUDIENCE" env-separator:","`
+	DefaultPolicy string        `yaml:"default_policy" env:"DEFAULT_POLICY" env-default:"authenticated"`
+}
+
+// GlobalConfig is set by the server main for code reading the config from
+// a global.
+//
+// Deprecated: pass the config explicitly.
 var GlobalConfig Config
 
+// ListenSpec returns spec, or a tcp spec of port if spec is empty.
+func ListenSpec(spec string, port int) string {
+	if spec != "" {
+		return spec
+	}
+	return fmt.Sprintf("tcp://:%d", port)
+}
+
 func (c *Config) Load(path string) error {
 	if path != "" {
 		err := cleanenv.ReadConfig(path, c)
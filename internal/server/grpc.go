@@ -2,63 +2,130 @@ package server
 
 import (
 	"context"
-	"fmt"
 	"net"
+	"net/http"
 
 	"buf.build/go/protovalidate"
 	grpcMiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpcRecovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
+	"github.com/pug-go/pug-template/pkg/accesslog"
+	"github.com/pug-go/pug-template/pkg/auth"
+	"github.com/pug-go/pug-template/pkg/healthcheck"
+	"github.com/pug-go/pug-template/pkg/i18n"
+	"github.com/pug-go/pug-template/pkg/inflight"
 	"github.com/pug-go/pug-template/pkg/interceptor"
+	"github.com/pug-go/pug-template/pkg/promlib"
+	"github.com/pug-go/pug-template/pkg/tlsconf"
 )
 
 type GrpcServer struct {
-	server             *grpc.Server
-	registerServicesFn func(server *grpc.Server)
+	server *grpc.Server
 }
 
-func NewGrpcServer(registerServicesFn func(server *grpc.Server)) (*GrpcServer, error) {
+// GrpcOptions of the grpc server, nil fields are not used unless noted.
+type GrpcOptions struct {
+	// Inflight counts calls, so that they are drained on shutdown, it's
+	// required.
+	Inflight *inflight.Counter
+	// TLS enables tls of the listener.
+	TLS *tlsconf.Reloader
+	// PeerTLS is the tls of connections of callers, identities are taken
+	// from client certificates it verifies. It's TLS, or the http tls in
+	// single port mode, where grpc calls are served by the http listener.
+	PeerTLS *tlsconf.Reloader
+	// Health is registered as grpc.health.v1.Health service, it's required.
+	Health *healthcheck.GrpcHealth
+	// Metrics records calls, nil means promlib.Default.
+	Metrics   *promlib.Metrics
+	AccessLog *accesslog.Logger
+	// Verifier enables auth of calls, authorization options of methods are
+	// checked without it too.
+	Verifier *auth.Verifier
+	// Requests is reported unary calls, see
+	// healthcheck.Watchdog.WatchActivity.
+	Requests *healthcheck.Activity
+}
+
+// NewGrpcServer creates grpc server with services registered by
+// registerServicesFn.
+func NewGrpcServer(registerServicesFn func(server *grpc.Server), options GrpcOptions) (*GrpcServer, error) {
 	validator, err := protovalidate.New()
 	if err != nil {
 		return nil, err
 	}
 
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(grpcMiddleware.ChainUnaryServer(
+			interceptor.UnaryServerRequestId(),
+			interceptor.UnaryServerInflight(options.Inflight),
+			interceptor.UnaryServerWatchdog(options.Requests),
+			interceptor.UnaryServerAccessLog(options.AccessLog),
+			interceptor.UnaryServerPrometheus(options.Metrics),
+			interceptor.UnaryServerIdentity(options.PeerTLS),
+			interceptor.UnaryServerAuth(options.Verifier),
+			interceptor.UnaryServerAuthorization(),
+			interceptor.UnaryServerLocale(i18n.Default),
+			interceptor.UnaryServerValidations(validator, i18n.Default, options.Metrics),
+			// put your interceptors here
+			grpcRecovery.UnaryServerInterceptor(), // should be last
+		)),
+		grpc.StreamInterceptor(grpcMiddleware.ChainStreamServer(
+			interceptor.StreamServerRequestId(),
+			interceptor.StreamServerInflight(options.Inflight),
+			interceptor.StreamServerAccessLog(options.AccessLog),
+			interceptor.StreamServerPrometheus(options.Metrics),
+			interceptor.StreamServerIdentity(options.PeerTLS),
+			interceptor.StreamServerAuth(options.Verifier),
+			interceptor.StreamServerAuthorization(),
+			interceptor.StreamServerLocale(i18n.Default),
+			interceptor.StreamServerValidations(validator, i18n.Default, options.Metrics),
+			// put your interceptors here
+			grpcRecovery.StreamServerInterceptor(), // should be last
+		)),
+	}
+	if options.TLS != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(options.TLS.ServerConfig())))
+	}
+
+	server := grpc.NewServer(opts...)
+	registerServicesFn(server)
+	options.Health.Register(server) // should be last
+
 	return &GrpcServer{
-		server: grpc.NewServer(
-			grpc.UnaryInterceptor(grpcMiddleware.ChainUnaryServer(
-				interceptor.UnaryServerPrometheus(),
-				interceptor.UnaryServerValidationsRu(validator),
-				// put your interceptors here
-				grpcRecovery.UnaryServerInterceptor(), // should be last
-			)),
-			grpc.StreamInterceptor(grpcMiddleware.ChainStreamServer(
-				interceptor.StreamServerPrometheus(),
-				interceptor.StreamServerValidationsRu(validator),
-				// put your interceptors here
-				grpcRecovery.StreamServerInterceptor(), // should be last
-			)),
-		),
-		registerServicesFn: registerServicesFn,
+		server: server,
 	}, nil
 }
 
-func (s *GrpcServer) Run(port int16) error {
-	address := fmt.Sprintf(":%d", port)
-
-	listener, err := net.Listen("tcp", address)
-	if err != nil {
-		return fmt.Errorf("failed to start gRPC server on %s: %w", address, err)
-	}
-
-	s.registerServicesFn(s.server)
-
-	log.Info("gRPC server listening on " + address)
+// Serve accepts grpc connections on the listener until Stop is called.
+func (s *GrpcServer) Serve(listener net.Listener) error {
+	log.Info("gRPC server listening on " + listener.Addr().String())
 	return s.server.Serve(listener)
 }
 
-func (s *GrpcServer) Stop(_ context.Context) error {
-	s.server.GracefulStop()
-	return nil
+// ServeHTTP serves a grpc request received by a http/2 server, used in
+// single port mode.
+func (s *GrpcServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.server.ServeHTTP(w, r)
+}
+
+// Stop stops the server gracefully, if ctx is done before, the remaining
+// connections are closed.
+func (s *GrpcServer) Stop(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
 }
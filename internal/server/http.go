@@ -3,16 +3,22 @@ package server
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 
+	"github.com/pug-go/pug-template/pkg/accesslog"
 	"github.com/pug-go/pug-template/pkg/gwopts"
+	"github.com/pug-go/pug-template/pkg/healthcheck"
 	"github.com/pug-go/pug-template/pkg/middleware"
+	"github.com/pug-go/pug-template/pkg/promlib"
+	"github.com/pug-go/pug-template/pkg/tlsconf"
 )
 
 type InitHttpRoutesFn func(mux *runtime.ServeMux, conn *grpc.ClientConn) error
@@ -22,13 +28,37 @@ type HttpServer struct {
 	initHttpRoutesFn InitHttpRoutesFn
 	middlewares      []func(next http.Handler) http.Handler
 	gwmux            *runtime.ServeMux
+	tlsReloader      *tlsconf.Reloader
+	grpcTLSReloader  *tlsconf.Reloader
 }
 
-func NewHttpServer(initHttpRoutesFn InitHttpRoutesFn) (*HttpServer, error) {
+// HttpOptions of the http gateway server, nil fields are not used unless
+// noted.
+type HttpOptions struct {
+	// TLS enables tls of the http listener, identities of callers are
+	// taken from client certificates it verifies.
+	TLS *tlsconf.Reloader
+	// GrpcTLS enables tls of the internal connection to grpc server.
+	GrpcTLS *tlsconf.Reloader
+	// Metrics records requests, nil means promlib.Default.
+	Metrics   *promlib.Metrics
+	AccessLog *accesslog.Logger
+	// Requests is reported requests, see
+	// healthcheck.Watchdog.WatchActivity.
+	Requests *healthcheck.Activity
+}
+
+// NewHttpServer creates http gateway server with routes initialized by
+// initHttpRoutesFn.
+func NewHttpServer(initHttpRoutesFn InitHttpRoutesFn, options HttpOptions) (*HttpServer, error) {
 	middlewares := middleware.New(
 		// put your http middlewares here
-		middleware.Default...,
+		middleware.NewDefault(options.Metrics)...,
 	)
+	middlewares = append(middlewares, middleware.NewIdentity(options.TLS), middleware.NewWatchdog(options.Requests))
+	// the access log wraps recovery, so that recovered panics are logged
+	// too, and the request id wraps the access log, so that records have it
+	middlewares = append(middlewares, middleware.NewAccessLog(options.AccessLog), middleware.RequestId)
 
 	gwmux := runtime.NewServeMux(
 		// put your opts here
@@ -43,28 +73,49 @@ func NewHttpServer(initHttpRoutesFn InitHttpRoutesFn) (*HttpServer, error) {
 		initHttpRoutesFn: initHttpRoutesFn,
 		middlewares:      middlewares,
 		gwmux:            gwmux,
+		tlsReloader:      options.TLS,
+		grpcTLSReloader:  options.GrpcTLS,
 	}, nil
 }
 
-func (s *HttpServer) Run(grpcPort, httpPort int16) error {
+// Serve accepts http connections on the listener until Stop is called,
+// requests are proxied to grpc server dialed by grpcTarget.
+func (s *HttpServer) Serve(listener net.Listener, grpcTarget string) error {
 	// create grpc client conn for internal http proxy
+	creds := insecure.NewCredentials()
+	if s.grpcTLSReloader != nil {
+		creds = credentials.NewTLS(s.grpcTLSReloader.ClientConfig())
+	}
 	conn, err := grpc.NewClient(
-		fmt.Sprintf("0.0.0.0:%d", grpcPort),
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpcTarget,
+		grpc.WithTransportCredentials(creds),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to dial internal grpc conn: %s", err)
 	}
 
-	err = s.initHttpRoutesFn(s.gwmux, conn)
+	s.server.Handler, err = s.Handler(conn)
 	if err != nil {
 		return err
 	}
-	s.server.Handler = s.applyMiddlewares(s.gwmux)
 
-	s.server.Addr = fmt.Sprintf(":%d", httpPort)
-	log.Info("http server listening on: ", s.server.Addr)
-	return s.server.ListenAndServe()
+	log.Info("http server listening on: ", listener.Addr().String())
+	if s.tlsReloader != nil {
+		s.server.TLSConfig = s.tlsReloader.ServerConfig()
+		return s.server.ServeTLS(listener, "", "")
+	}
+	return s.server.Serve(listener)
+}
+
+// Handler returns the gateway handler wrapped with middlewares, which
+// proxies requests to grpc through conn.
+func (s *HttpServer) Handler(conn *grpc.ClientConn) (http.Handler, error) {
+	err := s.initHttpRoutesFn(s.gwmux, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.applyMiddlewares(s.gwmux), nil
 }
 
 func (s *HttpServer) Stop(ctx context.Context) error {
@@ -0,0 +1,106 @@
+package accesslog
+
+import (
+	"math/rand/v2"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pug-go/pug-template/pkg/redact"
+)
+
+// Redacted replaces values of fields with the debug_redact option.
+const Redacted = redact.Placeholder
+
+// DefaultMaxPayloadSize is used when Config.MaxPayloadSize is not set.
+const DefaultMaxPayloadSize = 4096
+
+// Config of the access log. SampleRate from 0 to 1 applies to successful
+// requests only, failed ones are always logged. PayloadMethods are grpc full
+// methods, e.g. /pug.v1.PugService/HelloPug, or services, e.g.
+// pug.v1.PugService, which messages are logged too.
+type Config struct {
+	Enabled        bool
+	SampleRate     float64
+	PayloadMethods []string
+	MaxPayloadSize int
+}
+
+// Logger writes one record per request, a nil Logger writes nothing.
+type Logger struct {
+	cfg            Config
+	payloadMethods map[string]bool
+}
+
+func New(cfg Config) *Logger {
+	if cfg.MaxPayloadSize <= 0 {
+		cfg.MaxPayloadSize = DefaultMaxPayloadSize
+	}
+
+	payloadMethods := make(map[string]bool, len(cfg.PayloadMethods))
+	for _, method := range cfg.PayloadMethods {
+		if method = strings.TrimSpace(method); method != "" {
+			payloadMethods[strings.TrimPrefix(method, "/")] = true
+		}
+	}
+
+	return &Logger{
+		cfg:            cfg,
+		payloadMethods: payloadMethods,
+	}
+}
+
+// Enabled reports whether requests are logged at all.
+func (l *Logger) Enabled() bool {
+	return l != nil && l.cfg.Enabled
+}
+
+// Sampled reports whether the finished request is logged.
+func (l *Logger) Sampled(failed bool) bool {
+	if !l.Enabled() {
+		return false
+	}
+	if failed || l.cfg.SampleRate >= 1 {
+		return true
+	}
+	return rand.Float64() < l.cfg.SampleRate
+}
+
+// LogPayload reports whether messages of the grpc method are logged.
+func (l *Logger) LogPayload(fullMethod string) bool {
+	if !l.Enabled() || len(l.payloadMethods) == 0 {
+		return false
+	}
+
+	method := strings.TrimPrefix(fullMethod, "/")
+	service, _, _ := strings.Cut(method, "/")
+	return l.payloadMethods[method] || l.payloadMethods[service]
+}
+
+// Payload formats the message as json with sensitive fields redacted, see
+// redact.Message, truncated to the max payload size.
+func (l *Logger) Payload(m interface{}) string {
+	msg, ok := m.(proto.Message)
+	if !ok || msg == nil {
+		return ""
+	}
+
+	msg = proto.Clone(msg)
+	redact.Message(msg.ProtoReflect())
+	b, err := protojson.Marshal(msg)
+	if err != nil {
+		return ""
+	}
+
+	if len(b) > l.cfg.MaxPayloadSize {
+		return string(b[:l.cfg.MaxPayloadSize]) + "…"
+	}
+	return string(b)
+}
+
+// Log writes the record.
+func (l *Logger) Log(fields log.Fields) {
+	log.WithFields(fields).Info("access")
+}
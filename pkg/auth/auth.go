@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	Policy_Public        = "public"
+	Policy_Authenticated = "authenticated"
+	Policy_Internal      = "internal"
+)
+
+// MetadataKey is the grpc metadata key of the bearer token, the gateway
+// forwards the Authorization header with it.
+const MetadataKey = "authorization"
+
+// leeway tolerates clock skew of the token issuer.
+const leeway = 30 * time.Second
+
+// signing algorithms of the JWKS key types
+var validMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// Config of the token verification, auth is disabled if Enabled is false,
+// but authorization options of methods are enforced anyway, see Authorize.
+// Keys are loaded from JwksFile, or from JwksUrl if the file is empty.
+// Issuer and Audience are required, a token must be issued by Issuer for
+// one of the audiences, so that tokens of other services sharing the keys
+// are rejected. DefaultPolicy applies to methods without a policy in proto
+// options, it's authenticated if empty.
+type Config struct {
+	Enabled       bool
+	JwksFile      string
+	JwksUrl       string
+	JwksRefresh   time.Duration
+	Issuer        string
+	Audience      []string
+	DefaultPolicy string
+}
+
+// Claims of a verified token.
+type Claims struct {
+	jwt.RegisteredClaims
+	// Scope is a space separated list, see Scopes.
+	Scope       string   `json:"scope,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// Scopes returns the scopes of the scope claim.
+func (c *Claims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+// Verifier verifies bearer tokens with keys of a JWKS.
+type Verifier struct {
+	cfg    Config
+	keys   *KeySet
+	parser *jwt.Parser
+}
+
+// NewVerifier loads the JWKS described by cfg. It returns nil if auth is
+// disabled.
+func NewVerifier(cfg Config) (*Verifier, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if cfg.Issuer == "" {
+		return nil, errors.New("auth: issuer is required")
+	}
+	if len(cfg.Audience) == 0 {
+		return nil, errors.New("auth: audience is required")
+	}
+	if cfg.DefaultPolicy == "" {
+		cfg.DefaultPolicy = Policy_Authenticated
+	}
+	if _, err := ParsePolicy(cfg.DefaultPolicy); err != nil {
+		return nil, err
+	}
+
+	keys, err := NewKeySet(cfg.JwksFile, cfg.JwksUrl, cfg.JwksRefresh)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Verifier{
+		cfg:  cfg,
+		keys: keys,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(validMethods),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(leeway),
+			jwt.WithIssuer(cfg.Issuer),
+			jwt.WithAudience(cfg.Audience...),
+		),
+	}, nil
+}
+
+// Verify checks the signature, the expiration, the issuer and the audience
+// of the token and returns its claims.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := v.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// DefaultPolicy returns the policy of methods without one in proto options.
+func (v *Verifier) DefaultPolicy() string {
+	return v.cfg.DefaultPolicy
+}
+
+// BearerToken returns the token of the Authorization header value.
+func BearerToken(value string) (string, error) {
+	scheme, token, ok := strings.Cut(value, " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") || strings.TrimSpace(token) == "" {
+		return "", errors.New("authorization scheme must be bearer")
+	}
+	return strings.TrimSpace(token), nil
+}
+
+// Challenge formats the WWW-Authenticate header value of the error, see
+// RFC 6750.
+func Challenge(err error) string {
+	if err == nil {
+		return "Bearer"
+	}
+	return fmt.Sprintf(`Bearer error="invalid_token", error_description=%q`, err.Error())
+}
+
+type claimsKey struct{}
+
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims of the caller token, ok is false if
+// the caller didn't send a valid one.
+func ClaimsFromContext(ctx context.Context) (claims *Claims, ok bool) {
+	claims, ok = ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	optionsv1pb "github.com/pug-go/pug-template/gen/pug/options/v1"
+	"github.com/pug-go/pug-template/pkg/tlsconf"
+)
+
+// reasons of Denied, they are returned to clients in errorsv1pb.Denial
+//
+//goland:noinspection GoSnakeCaseUsage
+const (
+	Reason_MissingRole        = "MISSING_ROLE"
+	Reason_MissingScope       = "MISSING_SCOPE"
+	Reason_MissingPermission  = "MISSING_PERMISSION"
+	Reason_IdentityNotAllowed = "IDENTITY_NOT_ALLOWED"
+)
+
+// ErrNoCredentials is returned by Authorize if the caller has neither a
+// valid token nor a client certificate.
+var ErrNoCredentials = errors.New("auth: no credentials")
+
+// Denied is returned by Authorize if the caller isn't granted what the
+// method requires.
+type Denied struct {
+	Reason string
+	// Required are the missing roles, scopes or permissions.
+	Required []string
+}
+
+func (e *Denied) Error() string {
+	switch e.Reason {
+	case Reason_MissingRole:
+		return "one of roles is required: " + strings.Join(e.Required, ", ")
+	case Reason_MissingScope:
+		return "scopes are required: " + strings.Join(e.Required, ", ")
+	case Reason_MissingPermission:
+		return "permissions are required: " + strings.Join(e.Required, ", ")
+	}
+	return "caller is not allowed"
+}
+
+// Authorize checks the (pug.options.v1.method) authorization option of the
+// grpc method against the caller claims and identity in ctx. A client
+// certificate listed in identities is enough, otherwise the claims must
+// have any of the roles, all scopes and all permissions of the option. The
+// identity is only there for certificates verified against the client CA,
+// see tlsconf.Reloader.PeerIdentity, so that it can't be claimed by a
+// self-made certificate.
+func Authorize(ctx context.Context, fullMethod string) error {
+	authorization := MethodAuthorization(fullMethod)
+	if authorization == nil {
+		return nil
+	}
+
+	id, hasId := tlsconf.IdentityFromContext(ctx)
+	if hasId && matchIdentity(id, authorization.GetIdentities()) {
+		return nil
+	}
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		if hasId {
+			return &Denied{Reason: Reason_IdentityNotAllowed}
+		}
+		return ErrNoCredentials
+	}
+
+	return authorizeClaims(claims, authorization)
+}
+
+func authorizeClaims(claims *Claims, authorization *optionsv1pb.Authorization) error {
+	roles := authorization.GetRoles()
+	scopes := authorization.GetScopes()
+	permissions := authorization.GetPermissions()
+	// only client certificates are allowed
+	if len(roles) == 0 && len(scopes) == 0 && len(permissions) == 0 {
+		return &Denied{Reason: Reason_IdentityNotAllowed}
+	}
+
+	if len(roles) > 0 && !slices.ContainsFunc(roles, func(role string) bool {
+		return slices.Contains(claims.Roles, role)
+	}) {
+		return &Denied{Reason: Reason_MissingRole, Required: roles}
+	}
+	if missing := missing(scopes, claims.Scopes()); len(missing) > 0 {
+		return &Denied{Reason: Reason_MissingScope, Required: missing}
+	}
+	if missing := missing(permissions, claims.Permissions); len(missing) > 0 {
+		return &Denied{Reason: Reason_MissingPermission, Required: missing}
+	}
+
+	return nil
+}
+
+// missing returns the required values not granted.
+func missing(required, granted []string) []string {
+	var result []string
+	for _, value := range required {
+		if !slices.Contains(granted, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func matchIdentity(id *tlsconf.Identity, identities []string) bool {
+	for _, identity := range identities {
+		if id.CommonName == identity ||
+			slices.Contains(id.DNSNames, identity) ||
+			slices.Contains(id.URIs, identity) {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmpty(authorization *optionsv1pb.Authorization) bool {
+	return len(authorization.GetRoles()) == 0 &&
+		len(authorization.GetScopes()) == 0 &&
+		len(authorization.GetPermissions()) == 0 &&
+		len(authorization.GetIdentities()) == 0
+}
@@ -0,0 +1,293 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultJwksRefresh is used when Config.JwksRefresh is not set.
+const DefaultJwksRefresh = 5 * time.Minute
+
+// minJwksRefresh limits reloads forced by tokens with unknown key ids.
+const minJwksRefresh = 10 * time.Second
+
+// KeySet keeps public keys of a JWKS file or URL up to date. The file is
+// re-read when it changes on disk, the URL is fetched every refresh
+// interval and when a token is signed by an unknown key, so that rotated
+// keys are picked up.
+type KeySet struct {
+	file    string
+	url     string
+	refresh time.Duration
+	client  *http.Client
+
+	// reloading serializes reloads, they don't block verification with the
+	// current keys
+	reloading sync.Mutex
+	modTime   time.Time
+
+	mu        sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	checkedAt time.Time
+}
+
+// NewKeySet loads keys from file, or from url if file is empty.
+func NewKeySet(file, url string, refresh time.Duration) (*KeySet, error) {
+	if file == "" && url == "" {
+		return nil, errors.New("auth: jwks file or url is required")
+	}
+	if refresh <= 0 {
+		refresh = DefaultJwksRefresh
+	}
+
+	s := &KeySet{
+		file:    file,
+		url:     url,
+		refresh: refresh,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+	if err := s.reload(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Key returns the key with the id, empty id matches the only key of the
+// set.
+func (s *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	keys, checkedAt := s.current()
+	if time.Since(checkedAt) >= s.checkInterval() && s.reloading.TryLock() {
+		s.reloadLogged(ctx)
+		s.reloading.Unlock()
+		keys, _ = s.current()
+	}
+	if key, ok := lookupKey(keys, kid); ok {
+		return key, nil
+	}
+
+	// the key may be rotated, concurrent callers wait for one reload
+	s.reloading.Lock()
+	keys, checkedAt = s.current()
+	if _, ok := lookupKey(keys, kid); !ok && time.Since(checkedAt) >= minJwksRefresh {
+		s.reloadLogged(ctx)
+		keys, _ = s.current()
+	}
+	s.reloading.Unlock()
+	if key, ok := lookupKey(keys, kid); ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+func (s *KeySet) current() (map[string]crypto.PublicKey, time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keys, s.checkedAt
+}
+
+func lookupKey(keys map[string]crypto.PublicKey, kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+	key, ok := keys[kid]
+	return key, ok
+}
+
+// checkInterval is how often the source is checked, files are cheap to
+// stat.
+func (s *KeySet) checkInterval() time.Duration {
+	if s.file != "" {
+		return minJwksRefresh
+	}
+	return s.refresh
+}
+
+// reloadLogged reloads keys keeping the previous ones on errors.
+func (s *KeySet) reloadLogged(ctx context.Context) {
+	if err := s.reload(ctx); err != nil {
+		log.Errorf("auth: failed to reload jwks: %s", err)
+	}
+}
+
+// reload reads the keys, it's called with reloading locked or before the
+// set is used.
+func (s *KeySet) reload(ctx context.Context) error {
+	s.mu.Lock()
+	s.checkedAt = time.Now()
+	loaded := s.keys != nil
+	s.mu.Unlock()
+
+	var data []byte
+	if s.file != "" {
+		info, err := os.Stat(s.file)
+		if err != nil {
+			return fmt.Errorf("auth: %w", err)
+		}
+		if loaded && info.ModTime().Equal(s.modTime) {
+			return nil
+		}
+		if data, err = os.ReadFile(s.file); err != nil {
+			return fmt.Errorf("auth: %w", err)
+		}
+		s.modTime = info.ModTime()
+	} else {
+		var err error
+		if data, err = s.fetch(ctx); err != nil {
+			return err
+		}
+	}
+
+	keys, err := parseJwks(data)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	if loaded {
+		log.Info("auth: jwks reloaded, keys: ", len(keys))
+	}
+
+	return nil
+}
+
+func (s *KeySet) fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(context.WithoutCancel(ctx), http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("auth: failed to fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("auth: failed to fetch jwks: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("auth: failed to fetch jwks: %w", err)
+	}
+
+	return data, nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJwks parses RSA, EC and Ed25519 signing keys, other keys are
+// skipped.
+func parseJwks(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("auth: invalid jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			log.Errorf("auth: jwks key %q skipped: %s", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("auth: no signing keys in jwks")
+	}
+
+	return keys, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	optionsv1pb "github.com/pug-go/pug-template/gen/pug/options/v1"
+)
+
+// health checks are called by orchestrators without tokens
+const healthService = "grpc.health.v1.Health"
+
+// methodAuth are auth options of a method, policy is empty if the method
+// has none.
+type methodAuth struct {
+	policy        string
+	authorization *optionsv1pb.Authorization
+}
+
+var methods sync.Map // full method -> methodAuth
+
+// ParsePolicy parses a policy name of the config.
+func ParsePolicy(name string) (string, error) {
+	switch name {
+	case Policy_Public, Policy_Authenticated, Policy_Internal:
+		return name, nil
+	}
+	return "", fmt.Errorf("auth: unknown policy %q, must be %s, %s or %s",
+		name, Policy_Public, Policy_Authenticated, Policy_Internal)
+}
+
+// MethodPolicy returns the policy of the grpc method from its
+// (pug.options.v1.method) option, then from (pug.options.v1.service) option,
+// def is used when neither is set.
+func MethodPolicy(fullMethod, def string) string {
+	if policy := lookupMethod(fullMethod).policy; policy != "" {
+		return policy
+	}
+	return def
+}
+
+// MethodAuthorization returns the authorization option of the grpc method,
+// nil if the method has none.
+func MethodAuthorization(fullMethod string) *optionsv1pb.Authorization {
+	return lookupMethod(fullMethod).authorization
+}
+
+func lookupMethod(fullMethod string) methodAuth {
+	if m, ok := methods.Load(fullMethod); ok {
+		return m.(methodAuth)
+	}
+
+	m := findMethod(fullMethod)
+	methods.Store(fullMethod, m)
+
+	return m
+}
+
+func findMethod(fullMethod string) methodAuth {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if service == healthService {
+		return methodAuth{policy: Policy_Public}
+	}
+
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service + "." + method))
+	if err != nil {
+		return methodAuth{}
+	}
+	md, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok {
+		return methodAuth{}
+	}
+
+	return methodAuthOf(md)
+}
+
+// methodAuthOf reads the method and service options of md.
+func methodAuthOf(md protoreflect.MethodDescriptor) methodAuth {
+	sd := md.Parent().(protoreflect.ServiceDescriptor)
+
+	methodOpts, _ := proto.GetExtension(md.Options(), optionsv1pb.E_Method).(*optionsv1pb.Method)
+	m := methodAuth{authorization: methodOpts.GetAuthorization()}
+	if isEmpty(m.authorization) {
+		m.authorization = nil
+	}
+	if m.policy = policyName(methodOpts.GetAuth()); m.policy != "" {
+		return m
+	}
+	serviceOpts, _ := proto.GetExtension(sd.Options(), optionsv1pb.E_Service).(*optionsv1pb.Service)
+	m.policy = policyName(serviceOpts.GetAuth())
+
+	return m
+}
+
+func policyName(policy optionsv1pb.AuthPolicy) string {
+	switch policy {
+	case optionsv1pb.AuthPolicy_AUTH_POLICY_PUBLIC:
+		return Policy_Public
+	case optionsv1pb.AuthPolicy_AUTH_POLICY_AUTHENTICATED:
+		return Policy_Authenticated
+	case optionsv1pb.AuthPolicy_AUTH_POLICY_INTERNAL:
+		return Policy_Internal
+	}
+	return ""
+}
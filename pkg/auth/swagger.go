@@ -0,0 +1,324 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// SwaggerSecurityName is the security definition of bearer tokens.
+const SwaggerSecurityName = "bearer"
+
+// Swagger adds security of methods to the swagger spec generated by
+// protoc-gen-openapiv2 from files, so that it's documented by the same auth
+// and authorization options which are enforced. Operations requiring a
+// token reference the bearer security definition, the policy and
+// authorization options are added as x-pug-auth-policy and
+// x-pug-authorization extensions and to the description. defaultPolicy is
+// the policy of methods without one, as in Config. Order of keys is kept,
+// so that only the security of the spec changes.
+func Swagger(spec []byte, files *protoregistry.Files, defaultPolicy string) ([]byte, error) {
+	if _, err := ParsePolicy(defaultPolicy); err != nil {
+		return nil, err
+	}
+
+	var doc jsonObject
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("auth: invalid swagger spec: %w", err)
+	}
+
+	// fields in the order of protoc-gen-openapiv2
+	err := doc.set("securityDefinitions", map[string]any{
+		SwaggerSecurityName: struct {
+			Type        string `json:"type"`
+			Description string `json:"description"`
+			Name        string `json:"name"`
+			In          string `json:"in"`
+		}{
+			Type:        "apiKey",
+			Description: "JWT as: Bearer <token>",
+			Name:        "Authorization",
+			In:          "header",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var paths jsonObject
+	if err = doc.get("paths", &paths); err != nil {
+		return nil, err
+	}
+	for route, m := range swaggerRoutes(files) {
+		var item, op jsonObject
+		if err = paths.get(route.path, &item); err != nil {
+			return nil, err
+		}
+		if err = item.get(route.method, &op); err != nil {
+			return nil, err
+		}
+		if op == nil {
+			continue
+		}
+		if err = swaggerOperation(&op, m, defaultPolicy); err != nil {
+			return nil, err
+		}
+		if err = item.set(route.method, op); err != nil {
+			return nil, err
+		}
+		if err = paths.set(route.path, item); err != nil {
+			return nil, err
+		}
+	}
+	if err = doc.set("paths", paths); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(doc); err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func swaggerOperation(op *jsonObject, m methodAuth, defaultPolicy string) error {
+	policy := m.policy
+	if policy == "" {
+		policy = defaultPolicy
+	}
+	authorization := m.authorization
+
+	var notes []string
+	switch {
+	case policy == Policy_Internal:
+		notes = append(notes, "Requires a client certificate.")
+	case policy == Policy_Authenticated || authorization != nil:
+		err := op.set("security", []any{map[string]any{SwaggerSecurityName: []any{}}})
+		if err != nil {
+			return err
+		}
+		notes = append(notes, "Requires a bearer token.")
+	}
+	if err := op.set("x-pug-auth-policy", policy); err != nil {
+		return err
+	}
+
+	if authorization != nil {
+		extension := map[string]any{}
+		if roles := authorization.GetRoles(); len(roles) > 0 {
+			extension["roles"] = roles
+			notes = append(notes, "Requires one of roles: "+strings.Join(roles, ", ")+".")
+		}
+		if scopes := authorization.GetScopes(); len(scopes) > 0 {
+			extension["scopes"] = scopes
+			notes = append(notes, "Requires scopes: "+strings.Join(scopes, ", ")+".")
+		}
+		if permissions := authorization.GetPermissions(); len(permissions) > 0 {
+			extension["permissions"] = permissions
+			notes = append(notes, "Requires permissions: "+strings.Join(permissions, ", ")+".")
+		}
+		if identities := authorization.GetIdentities(); len(identities) > 0 {
+			extension["identities"] = identities
+			notes = append(notes, "Allows client certificates of: "+strings.Join(identities, ", ")+".")
+		}
+		if err := op.set("x-pug-authorization", extension); err != nil {
+			return err
+		}
+	}
+
+	if len(notes) == 0 {
+		return nil
+	}
+	var description string
+	if err := op.get("description", &description); err != nil {
+		return err
+	}
+	if description != "" {
+		description += "\n\n"
+	}
+	// the description follows the summary, as protoc-gen-openapiv2 puts it
+	return op.setAfter("summary", "description", description+strings.Join(notes, " "))
+}
+
+// jsonObject is a json object which keeps the order of its keys, values
+// are kept as they are unless set.
+type jsonObject []jsonField
+
+type jsonField struct {
+	key   string
+	value json.RawMessage
+}
+
+func (o *jsonObject) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return fmt.Errorf("auth: json object expected")
+	}
+	*o = (*o)[:0]
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := token.(string)
+		var value json.RawMessage
+		if err = decoder.Decode(&value); err != nil {
+			return err
+		}
+		*o = append(*o, jsonField{key: key, value: value})
+	}
+	return nil
+}
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(field.key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(field.value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (o jsonObject) index(key string) int {
+	for i, field := range o {
+		if field.key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// get decodes the value of key into v, v is left as is if there's no key.
+func (o jsonObject) get(key string, v any) error {
+	i := o.index(key)
+	if i < 0 {
+		return nil
+	}
+	if err := json.Unmarshal(o[i].value, v); err != nil {
+		return fmt.Errorf("auth: invalid swagger spec: %s: %w", key, err)
+	}
+	return nil
+}
+
+// set replaces the value of key in place or adds it to the end.
+func (o *jsonObject) set(key string, v any) error {
+	return o.setAt(len(*o), key, v)
+}
+
+// setAfter replaces the value of key in place or adds it after the after
+// key, or to the start if there's none.
+func (o *jsonObject) setAfter(after, key string, v any) error {
+	return o.setAt(o.index(after)+1, key, v)
+}
+
+func (o *jsonObject) setAt(i int, key string, v any) error {
+	value, err := marshalSwagger(v)
+	if err != nil {
+		return err
+	}
+	if j := o.index(key); j >= 0 {
+		(*o)[j].value = value
+		return nil
+	}
+	*o = slices.Insert(*o, i, jsonField{key: key, value: value})
+	return nil
+}
+
+// marshalSwagger marshals v without escaping of html characters, like
+// protoc-gen-openapiv2 does.
+func marshalSwagger(v any) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+	return bytes.TrimSpace(buf.Bytes()), nil
+}
+
+// swaggerRoute is an operation of the swagger spec, method is lowercase.
+type swaggerRoute struct {
+	method string
+	path   string
+}
+
+// swaggerRoutes maps http bindings of methods to their auth options. Routes
+// are unique, unlike operation ids of services with the same name in
+// different packages.
+func swaggerRoutes(files *protoregistry.Files) map[swaggerRoute]methodAuth {
+	routes := make(map[swaggerRoute]methodAuth)
+	files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		for i := 0; i < file.Services().Len(); i++ {
+			sd := file.Services().Get(i)
+			for j := 0; j < sd.Methods().Len(); j++ {
+				md := sd.Methods().Get(j)
+				rule, _ := proto.GetExtension(md.Options(), annotations.E_Http).(*annotations.HttpRule)
+				if rule == nil {
+					continue
+				}
+
+				m := methodAuthOf(md)
+				for _, binding := range append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...) {
+					method, template := httpRule(binding)
+					if method == "" {
+						continue
+					}
+					routes[swaggerRoute{
+						method: strings.ToLower(method),
+						path:   swaggerPath(template),
+					}] = m
+				}
+			}
+		}
+		return true
+	})
+	return routes
+}
+
+func httpRule(rule *annotations.HttpRule) (method, template string) {
+	switch pattern := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		return http.MethodGet, pattern.Get
+	case *annotations.HttpRule_Put:
+		return http.MethodPut, pattern.Put
+	case *annotations.HttpRule_Post:
+		return http.MethodPost, pattern.Post
+	case *annotations.HttpRule_Delete:
+		return http.MethodDelete, pattern.Delete
+	case *annotations.HttpRule_Patch:
+		return http.MethodPatch, pattern.Patch
+	case *annotations.HttpRule_Custom:
+		return pattern.Custom.GetKind(), pattern.Custom.GetPath()
+	}
+	return "", ""
+}
+
+var templateVariable = regexp.MustCompile(`\{([^=}]+)=[^}]*}`)
+
+// swaggerPath converts a path template like protoc-gen-openapiv2 does:
+// {name=shelves/*} becomes {name}.
+func swaggerPath(template string) string {
+	return templateVariable.ReplaceAllString(template, "{$1}")
+}
@@ -1,40 +1,182 @@
 package closer
 
 import (
-	log "github.com/sirupsen/logrus"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
 )
 
+// PriorityDefault is the priority of entries added without WithPriority.
+const PriorityDefault = 0
+
 var globalCloser = NewCloser()
 
+// Closer closes resources on shutdown. Entries are closed by priority groups,
+// the highest priority first, and entries of a group are closed in parallel.
+// The exception is the PriorityDefault group of entries added without
+// WithPriority: they are closed one by one in LIFO order, so that a resource
+// is closed before the ones it was built on, e.g. a repository before its
+// database pool. Use a custom priority for independent entries that should
+// close in parallel.
 type Closer struct {
-	fns []func() error
+	mutex   sync.Mutex
+	entries []*entry
+}
+
+type entry struct {
+	name     string
+	fn       func(ctx context.Context) error
+	priority int
+	timeout  time.Duration
+}
+
+// Option configures an entry.
+type Option func(e *entry)
+
+// WithPriority puts the entry into the priority group, higher priorities
+// are closed earlier.
+func WithPriority(priority int) Option {
+	return func(e *entry) {
+		e.priority = priority
+	}
+}
+
+// WithTimeout limits closing of the entry, the deadline of the CloseAll
+// context applies too.
+func WithTimeout(timeout time.Duration) Option {
+	return func(e *entry) {
+		e.timeout = timeout
+	}
 }
 
 func NewCloser() *Closer {
 	return &Closer{}
 }
 
-func Add(f ...func() error) {
-	globalCloser.Add(f...)
+// Add adds fn to the global closer, see Closer.Add.
+//
+// Deprecated: add resources to the closer of the app, see pug.App.Closer.
+func Add(name string, fn func(ctx context.Context) error, opts ...Option) {
+	globalCloser.Add(name, fn, opts...)
+}
+
+// AddFunc adds fn to the global closer, see Closer.AddFunc.
+//
+// Deprecated: add resources to the closer of the app, see pug.App.Closer.
+func AddFunc(name string, fn func() error, opts ...Option) {
+	globalCloser.AddFunc(name, fn, opts...)
 }
 
-func CloseAll() {
-	globalCloser.CloseAll()
+// CloseAll closes the global closer, see Closer.CloseAll. It's called by
+// the process main after the app is stopped, apps close only their own
+// closers.
+func CloseAll(ctx context.Context) error {
+	return globalCloser.CloseAll(ctx)
+}
+
+// Add adds a named entry, it's safe to call concurrently. fn should return
+// when ctx is done, CloseAll doesn't wait for it longer anyway.
+func (c *Closer) Add(name string, fn func(ctx context.Context) error, opts ...Option) {
+	e := &entry{
+		name:     name,
+		fn:       fn,
+		priority: PriorityDefault,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	c.mutex.Lock()
+	c.entries = append(c.entries, e)
+	c.mutex.Unlock()
 }
 
-func (c *Closer) Add(f ...func() error) {
-	c.fns = append(c.fns, f...)
+// AddFunc adds a named entry which doesn't take a context, e.g. io.Closer.
+func (c *Closer) AddFunc(name string, fn func() error, opts ...Option) {
+	c.Add(name, func(_ context.Context) error {
+		return fn()
+	}, opts...)
 }
 
-func (c *Closer) CloseAll() {
-	errs := make([]error, len(c.fns))
-	for _, f := range c.fns {
-		errs = append(errs, f())
+// CloseAll closes all entries added so far and returns errors.Join of their
+// errors, each prefixed with the entry name. Entries are removed, so a
+// second call closes only the ones added after the first.
+func (c *Closer) CloseAll(ctx context.Context) error {
+	c.mutex.Lock()
+	entries := c.entries
+	c.entries = nil
+	c.mutex.Unlock()
+
+	// LIFO, stable sort keeps it within a group
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
 	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].priority > entries[j].priority
+	})
+
+	var errs []error
+	for len(entries) > 0 {
+		n := 1
+		for n < len(entries) && entries[n].priority == entries[0].priority {
+			n++
+		}
+		group := entries[:n]
+		entries = entries[n:]
+
+		// dependent resources, see Closer
+		if group[0].priority == PriorityDefault {
+			for _, e := range group {
+				errs = append(errs, e.close(ctx))
+			}
+			continue
+		}
 
-	for _, err := range errs {
-		if err != nil {
-			log.Errorf("error returned from closer: %s", err)
+		groupErrs := make([]error, len(group))
+		var wg sync.WaitGroup
+		for i, e := range group {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				groupErrs[i] = e.close(ctx)
+			}()
 		}
+		wg.Wait()
+		errs = append(errs, groupErrs...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// close runs the entry until it returns or its context is done.
+func (e *entry) close(ctx context.Context) error {
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- e.fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", e.name, err)
 	}
+	return nil
 }
@@ -0,0 +1,40 @@
+package gwopts
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// FromGatewayMetadata marks grpc calls made by the gateway. Its value is a
+// random secret of the process, so that direct grpc clients can't pass for
+// the gateway by sending the key.
+const FromGatewayMetadata = "x-from-grpc-gateway"
+
+var gatewaySecret = newGatewaySecret()
+
+func newGatewaySecret() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func markFromGateway(_ context.Context, _ *http.Request) metadata.MD {
+	return metadata.Pairs(FromGatewayMetadata, gatewaySecret)
+}
+
+// IsFromGateway reports whether the grpc call is made by the gateway of
+// this process.
+func IsFromGateway(ctx context.Context) bool {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get(FromGatewayMetadata) {
+		if subtle.ConstantTimeCompare([]byte(value), []byte(gatewaySecret)) == 1 {
+			return true
+		}
+	}
+	return false
+}
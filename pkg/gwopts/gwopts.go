@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
 
 	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
@@ -13,14 +12,24 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+
+	errorsv1pb "github.com/pug-go/pug-template/gen/pug/errors/v1"
+	"github.com/pug-go/pug-template/pkg/i18n"
+	"github.com/pug-go/pug-template/pkg/requestid"
+	"github.com/pug-go/pug-template/pkg/tlsconf"
+	"github.com/pug-go/pug-template/pkg/violations"
 )
 
 var Default = []runtime.ServeMuxOption{
 	runtime.WithErrorHandler(handleHttpError),
-	runtime.WithMetadata(func(ctx context.Context, req *http.Request) metadata.MD {
-		return metadata.Pairs("x-from-grpc-gateway", "true")
-	}),
+	runtime.WithMiddlewares(strictJson),
+	runtime.WithMetadata(markFromGateway),
+	runtime.WithMetadata(forwardClientCert),
+	runtime.WithMetadata(forwardLocale),
+	runtime.WithMetadata(forwardRequestId),
+	runtime.WithOutgoingHeaderMatcher(outgoingHeader),
 	runtime.WithForwardResponseOption(func(ctx context.Context, writer http.ResponseWriter, message proto.Message) error {
 		pattern, ok := runtime.HTTPPathPattern(ctx)
 		if ok {
@@ -30,6 +39,70 @@ var Default = []runtime.ServeMuxOption{
 	}),
 }
 
+// forwardClientCert passes the verified http client certificate to grpc, see
+// middleware.NewIdentity. The header is always set, so that a value sent by
+// the client can't be taken for it.
+func forwardClientCert(ctx context.Context, _ *http.Request) metadata.MD {
+	var value string
+	if id, ok := tlsconf.IdentityFromContext(ctx); ok {
+		value = tlsconf.EncodeCert(id.Certificate)
+	}
+	return metadata.Pairs(tlsconf.ForwardedCertHeader, value)
+}
+
+// forwardLocale passes the http Accept-Language header to grpc, see
+// interceptor.UnaryServerLocale.
+func forwardLocale(_ context.Context, req *http.Request) metadata.MD {
+	value := req.Header.Get("Accept-Language")
+	if value == "" {
+		return nil
+	}
+	return metadata.Pairs(i18n.MetadataKey, value)
+}
+
+// forwardRequestId passes the request id and the source to grpc, see
+// middleware.RequestId.
+func forwardRequestId(ctx context.Context, req *http.Request) metadata.MD {
+	md := metadata.MD{}
+	if id := requestid.FromContext(ctx); id != "" {
+		md.Set(requestid.MetadataKey, id)
+	}
+	if source := requestid.SourceFromContext(ctx); source != "" {
+		md.Set(requestid.SourceMetadata, source)
+	}
+	return md
+}
+
+// outgoingHeader forwards grpc response headers like the default matcher,
+// except the request id which is set by middleware.RequestId.
+func outgoingHeader(key string) (string, bool) {
+	if key == requestid.MetadataKey {
+		return "", false
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+// errorBody returns the json body of an error response with the request id,
+// so that clients can report it.
+func errorBody(r *http.Request, code int, message string) map[string]any {
+	body := map[string]any{
+		"code":    code,
+		"message": message,
+	}
+	if id := requestid.FromContext(r.Context()); id != "" {
+		body["request_id"] = id
+	}
+	return body
+}
+
+// statusText returns the localized generic text of the http code.
+func statusText(locale string, code int) string {
+	if text, ok := i18n.Default.Render(locale, fmt.Sprintf("http.%d", code), nil); ok {
+		return text
+	}
+	return http.StatusText(code)
+}
+
 func handleHttpError(
 	ctx context.Context,
 	mux *runtime.ServeMux,
@@ -39,13 +112,16 @@ func handleHttpError(
 	err error,
 ) {
 	if s, ok := status.FromError(err); ok {
+		locale := i18n.Default.Negotiate(r.Header.Get("Accept-Language"))
+		w.Header().Set("Content-Language", locale)
+
 		if s.Code() == codes.Internal {
 			// remove internal error from http response, but send to logs
 			log.Error(s.Message())
 
 			w.Header().Set("Content-Type", "application/json; charset=utf-8")
 			w.WriteHeader(http.StatusInternalServerError)
-			_, err = w.Write([]byte(`{"code": 500, "message": "Internal Server Error"}`))
+			err = json.NewEncoder(w).Encode(errorBody(r, http.StatusInternalServerError, statusText(locale, http.StatusInternalServerError)))
 			if err != nil {
 				log.Error(err)
 			}
@@ -53,27 +129,28 @@ func handleHttpError(
 		}
 		if s.Code() == codes.InvalidArgument {
 			errorsMap := map[string][]string{}
-			var violations *validate.Violations
-
-			for _, detail := range s.Details() {
-				if violations, ok = detail.(*validate.Violations); ok {
-					for _, violation := range violations.GetViolations() {
-						field := fieldPath(violation)
-						if field == "" {
-							field = "unknown"
-						}
-						errorsMap[field] = append(errorsMap[field], violation.GetMessage())
-					}
+			items := []json.RawMessage{}
+			for _, violation := range violationsOf(s) {
+				field := violation.GetField()
+				if field == "" {
+					field = "unknown"
 				}
+				errorsMap[field] = append(errorsMap[field], violation.GetMessage())
+
+				item, merr := violationJson.Marshal(violation)
+				if merr != nil {
+					log.Error(merr)
+					continue
+				}
+				items = append(items, item)
 			}
 
 			w.Header().Set("Content-Type", "application/json; charset=utf-8")
 			w.WriteHeader(http.StatusBadRequest)
-			err = json.NewEncoder(w).Encode(map[string]any{
-				"code":    400,
-				"message": "Bad Request",
-				"errors":  errorsMap,
-			})
+			body := errorBody(r, http.StatusBadRequest, statusText(locale, http.StatusBadRequest))
+			body["errors"] = errorsMap
+			body["violations"] = items
+			err = json.NewEncoder(w).Encode(body)
 			if err != nil {
 				log.Error(err)
 			}
@@ -83,16 +160,19 @@ func handleHttpError(
 		// all other errors
 		httpCode := runtime.HTTPStatusFromCode(s.Code())
 		w.Header().Set("Content-Type", "application/json; charset=utf-8")
-		w.WriteHeader(httpCode)
-
 		if s.Code() == codes.Unauthenticated {
 			w.Header().Set("WWW-Authenticate", s.Message())
 		}
+		w.WriteHeader(httpCode)
 
-		err = json.NewEncoder(w).Encode(map[string]any{
-			"code":    httpCode,
-			"message": http.StatusText(httpCode),
-		})
+		body := errorBody(r, httpCode, statusText(locale, httpCode))
+		if denial := denialOf(s); denial != nil {
+			body["reason"] = denial.GetReason()
+			if len(denial.GetRequired()) > 0 {
+				body["required"] = denial.GetRequired()
+			}
+		}
+		err = json.NewEncoder(w).Encode(body)
 		if err != nil {
 			log.Error(err)
 		}
@@ -102,23 +182,40 @@ func handleHttpError(
 	runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
 }
 
-func fieldPath(v *validate.Violation) string {
-	if v.GetField() == nil || len(v.GetField().GetElements()) == 0 {
-		return ""
-	}
+// violationJson has the same field names as the grpc detail.
+var violationJson = protojson.MarshalOptions{UseProtoNames: true}
 
-	var parts []string
-	for _, el := range v.GetField().GetElements() {
-		name := el.GetFieldName()
-		if name == "" {
-			continue
+// violationsOf returns the structured violations of the status. Statuses
+// not made by the validation interceptor have only protovalidate details,
+// they are converted without rule values.
+func violationsOf(s *status.Status) []*errorsv1pb.Violation {
+	var result []*errorsv1pb.Violation
+	for _, detail := range s.Details() {
+		if v, ok := detail.(*errorsv1pb.Violations); ok {
+			return v.GetViolations()
 		}
-
-		if el.GetSubscript() != nil { // e.g. repeated
-			name = fmt.Sprintf("%s[%d]", name, el.GetIndex())
+		if v, ok := detail.(*validate.Violations); ok {
+			for _, violation := range v.GetViolations() {
+				result = append(result, &errorsv1pb.Violation{
+					Field:   violations.FieldPath(violation.GetField()),
+					RuleId:  violation.GetRuleId(),
+					Message: violation.GetMessage(),
+					ForKey:  violation.GetForKey(),
+				})
+			}
 		}
-		parts = append(parts, name)
 	}
 
-	return strings.Join(parts, ".")
+	return result
+}
+
+// denialOf returns the detail of PermissionDenied errors of the auth
+// interceptor, nil for other statuses.
+func denialOf(s *status.Status) *errorsv1pb.Denial {
+	for _, detail := range s.Details() {
+		if denial, ok := detail.(*errorsv1pb.Denial); ok {
+			return denial
+		}
+	}
+	return nil
 }
@@ -0,0 +1,489 @@
+package gwopts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	errorsv1pb "github.com/pug-go/pug-template/gen/pug/errors/v1"
+	optionsv1pb "github.com/pug-go/pug-template/gen/pug/options/v1"
+	"github.com/pug-go/pug-template/pkg/i18n"
+)
+
+// rule ids of strict json violations, they are i18n catalog keys too
+const (
+	ruleJsonSyntax       = "json.syntax"
+	ruleJsonUnknownField = "json.unknown_field"
+	ruleJsonDuplicateKey = "json.duplicate_key"
+	ruleJsonType         = "json.type"
+	ruleJsonEnum         = "json.enum"
+)
+
+// JsonErrorInfo is the data of json.* message templates, see i18n.Catalog.
+type JsonErrorInfo struct {
+	FieldPath string
+	// Expected is the json type of the field: string, boolean, integer,
+	// number, object or array.
+	Expected string
+}
+
+// strictRoute is a gateway route of a method with strict_json option.
+type strictRoute struct {
+	input protoreflect.MessageDescriptor
+	// body is the request field mapped to the http body, nil for "*"
+	body protoreflect.FieldDescriptor
+}
+
+var (
+	strictRoutesOnce sync.Once
+	strictRoutes     map[string]strictRoute
+)
+
+// strictJson rejects request bodies of methods with (pug.options.v1.method)
+// or (pug.options.v1.service) strict_json option, which protojson would
+// decode ignoring unknown fields or fail without a field path. Violations
+// are responded like the validation ones.
+func strictJson(next runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		route, ok := lookupStrictRoute(r)
+		if !ok || r.Body == nil || !isJsonContent(r) {
+			next(w, r, pathParams)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		_ = r.Body.Close()
+		if err != nil {
+			handleHttpError(r.Context(), nil, nil, w, r, status.Error(codes.InvalidArgument, err.Error()))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		locale := i18n.Default.Negotiate(r.Header.Get("Accept-Language"))
+		checker := newJsonChecker(body, locale)
+		if checker.check(route) {
+			next(w, r, pathParams)
+			return
+		}
+
+		st, err := status.New(codes.InvalidArgument, "invalid json body").
+			WithDetails(&errorsv1pb.Violations{Violations: checker.violations})
+		if err != nil {
+			st = status.New(codes.InvalidArgument, "invalid json body")
+		}
+		// the error handler doesn't need mux and marshaler for statuses
+		handleHttpError(r.Context(), nil, nil, w, r, st.Err())
+	}
+}
+
+func isJsonContent(r *http.Request) bool {
+	contentType := r.Header.Get("Content-Type")
+	return contentType == "" || strings.Contains(contentType, "json")
+}
+
+func lookupStrictRoute(r *http.Request) (strictRoute, bool) {
+	pattern, ok := runtime.HTTPPattern(r.Context())
+	if !ok {
+		return strictRoute{}, false
+	}
+
+	strictRoutesOnce.Do(func() {
+		strictRoutes = findStrictRoutes(protoregistry.GlobalFiles)
+	})
+	route, ok := strictRoutes[r.Method+" "+pattern.String()]
+
+	return route, ok
+}
+
+// findStrictRoutes indexes http bindings of strict methods by http method
+// and path pattern in the runtime.Pattern format.
+func findStrictRoutes(files *protoregistry.Files) map[string]strictRoute {
+	routes := make(map[string]strictRoute)
+	files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		for i := 0; i < file.Services().Len(); i++ {
+			sd := file.Services().Get(i)
+			serviceOpts, _ := proto.GetExtension(sd.Options(), optionsv1pb.E_Service).(*optionsv1pb.Service)
+
+			for j := 0; j < sd.Methods().Len(); j++ {
+				md := sd.Methods().Get(j)
+				methodOpts, _ := proto.GetExtension(md.Options(), optionsv1pb.E_Method).(*optionsv1pb.Method)
+				strict := serviceOpts.GetStrictJson()
+				if methodOpts != nil && methodOpts.StrictJson != nil {
+					strict = methodOpts.GetStrictJson()
+				}
+				if !strict {
+					continue
+				}
+
+				rule, _ := proto.GetExtension(md.Options(), annotations.E_Http).(*annotations.HttpRule)
+				if rule == nil {
+					continue
+				}
+				for _, binding := range append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...) {
+					method, template := httpRulePattern(binding)
+					if method == "" || binding.GetBody() == "" {
+						continue
+					}
+
+					route := strictRoute{input: md.Input()}
+					if body := binding.GetBody(); body != "*" {
+						route.body = md.Input().Fields().ByName(protoreflect.Name(body))
+						if route.body == nil {
+							continue
+						}
+					}
+					routes[method+" "+normalizeTemplate(template)] = route
+				}
+			}
+		}
+		return true
+	})
+
+	return routes
+}
+
+func httpRulePattern(rule *annotations.HttpRule) (method, template string) {
+	switch p := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		return http.MethodGet, p.Get
+	case *annotations.HttpRule_Put:
+		return http.MethodPut, p.Put
+	case *annotations.HttpRule_Post:
+		return http.MethodPost, p.Post
+	case *annotations.HttpRule_Delete:
+		return http.MethodDelete, p.Delete
+	case *annotations.HttpRule_Patch:
+		return http.MethodPatch, p.Patch
+	case *annotations.HttpRule_Custom:
+		return p.Custom.GetKind(), p.Custom.GetPath()
+	}
+	return "", ""
+}
+
+var shortVariable = regexp.MustCompile(`\{([^=}]+)\}`)
+
+// normalizeTemplate formats the path template like runtime.Pattern does,
+// {name} is {name=*}.
+func normalizeTemplate(template string) string {
+	return shortVariable.ReplaceAllString(template, "{$1=*}")
+}
+
+// jsonChecker walks a json value along the message descriptor following the
+// protojson mapping.
+type jsonChecker struct {
+	dec        *json.Decoder
+	locale     string
+	violations []*errorsv1pb.Violation
+}
+
+func newJsonChecker(body []byte, locale string) *jsonChecker {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	return &jsonChecker{dec: dec, locale: locale}
+}
+
+// check returns false if the body has violations.
+func (c *jsonChecker) check(route strictRoute) bool {
+	tok, err := c.dec.Token()
+	if err == io.EOF {
+		// empty body is an empty message for the gateway
+		return true
+	}
+	if err != nil {
+		c.add("", ruleJsonSyntax, "")
+		return false
+	}
+
+	var ok bool
+	if route.body == nil {
+		ok = c.message(tok, route.input, "")
+	} else {
+		ok = c.field(tok, route.body, string(route.body.Name()))
+	}
+	if ok {
+		if _, err = c.dec.Token(); err != io.EOF {
+			c.add("", ruleJsonSyntax, "")
+		}
+	}
+
+	return len(c.violations) == 0
+}
+
+// token reads the next token, false means the json is malformed.
+func (c *jsonChecker) token(path string) (json.Token, bool) {
+	tok, err := c.dec.Token()
+	if err != nil {
+		c.add(path, ruleJsonSyntax, "")
+		return nil, false
+	}
+	return tok, true
+}
+
+func (c *jsonChecker) message(tok json.Token, md protoreflect.MessageDescriptor, path string) bool {
+	if tok == nil {
+		return true
+	}
+	if md.FullName().Parent() == "google.protobuf" {
+		// well known types have own json mapping, protojson reports them
+		return c.skip(tok, path)
+	}
+	if tok != json.Delim('{') {
+		c.add(path, ruleJsonType, "object")
+		return c.skip(tok, path)
+	}
+
+	seen := make(map[protoreflect.FieldNumber]bool)
+	for c.dec.More() {
+		keyTok, ok := c.token(path)
+		if !ok {
+			return false
+		}
+		key := keyTok.(string)
+
+		fd := md.Fields().ByJSONName(key)
+		if fd == nil {
+			fd = md.Fields().ByName(protoreflect.Name(key))
+		}
+		if fd == nil {
+			fieldPath := joinPath(path, key)
+			c.add(fieldPath, ruleJsonUnknownField, "")
+			if tok, ok = c.token(fieldPath); !ok || !c.skip(tok, fieldPath) {
+				return false
+			}
+			continue
+		}
+
+		fieldPath := joinPath(path, string(fd.Name()))
+		if seen[fd.Number()] {
+			c.add(fieldPath, ruleJsonDuplicateKey, "")
+		}
+		seen[fd.Number()] = true
+
+		if tok, ok = c.token(fieldPath); !ok || !c.field(tok, fd, fieldPath) {
+			return false
+		}
+	}
+
+	_, ok := c.token(path)
+	return ok
+}
+
+func (c *jsonChecker) field(tok json.Token, fd protoreflect.FieldDescriptor, path string) bool {
+	switch {
+	case tok == nil:
+		return true
+	case fd.IsMap():
+		if tok != json.Delim('{') {
+			c.add(path, ruleJsonType, "object")
+			return c.skip(tok, path)
+		}
+
+		seen := make(map[string]bool)
+		for c.dec.More() {
+			keyTok, ok := c.token(path)
+			if !ok {
+				return false
+			}
+			key := keyTok.(string)
+
+			keyPath := fmt.Sprintf("%s[%s]", path, key)
+			if fd.MapKey().Kind() == protoreflect.StringKind {
+				keyPath = fmt.Sprintf("%s[%s]", path, strconv.Quote(key))
+			} else if !isScalar(key, fd.MapKey()) {
+				c.add(keyPath, ruleJsonType, expectedType(fd.MapKey()))
+			}
+			if seen[key] {
+				c.add(keyPath, ruleJsonDuplicateKey, "")
+			}
+			seen[key] = true
+
+			if tok, ok = c.token(keyPath); !ok || !c.single(tok, fd.MapValue(), keyPath) {
+				return false
+			}
+		}
+
+		_, ok := c.token(path)
+		return ok
+	case fd.IsList():
+		if tok != json.Delim('[') {
+			c.add(path, ruleJsonType, "array")
+			return c.skip(tok, path)
+		}
+
+		for i := 0; c.dec.More(); i++ {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			item, ok := c.token(itemPath)
+			if !ok || !c.single(item, fd, itemPath) {
+				return false
+			}
+		}
+
+		_, ok := c.token(path)
+		return ok
+	default:
+		return c.single(tok, fd, path)
+	}
+}
+
+// single checks a value of a singular field, a list item or a map value.
+func (c *jsonChecker) single(tok json.Token, fd protoreflect.FieldDescriptor, path string) bool {
+	if tok == nil {
+		return true
+	}
+
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return c.message(tok, fd.Message(), path)
+	case protoreflect.EnumKind:
+		switch v := tok.(type) {
+		case string:
+			if fd.Enum().Values().ByName(protoreflect.Name(v)) == nil {
+				c.add(path, ruleJsonEnum, "")
+			}
+			return true
+		case json.Number:
+			if !isScalar(v.String(), fd) {
+				c.add(path, ruleJsonType, expectedType(fd))
+			}
+			return true
+		}
+	case protoreflect.BoolKind:
+		if _, ok := tok.(bool); ok {
+			return true
+		}
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		if _, ok := tok.(string); ok {
+			return true
+		}
+	default:
+		// numbers may be quoted
+		var value string
+		switch v := tok.(type) {
+		case json.Number:
+			value = v.String()
+		case string:
+			value = v
+		}
+		if value != "" && isScalar(value, fd) {
+			return true
+		}
+	}
+
+	c.add(path, ruleJsonType, expectedType(fd))
+	return c.skip(tok, path)
+}
+
+// scalar checks a number or a map key like protojson parses it.
+func isScalar(value string, fd protoreflect.FieldDescriptor) bool {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		_, err := strconv.ParseBool(value)
+		return err == nil && (value == "true" || value == "false")
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind, protoreflect.EnumKind:
+		return isInteger(value, math.MinInt32, math.MaxInt32)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return isInteger(value, math.MinInt64, math.MaxInt64)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return isInteger(value, 0, math.MaxUint32)
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if _, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return true
+		}
+		return isInteger(value, 0, math.MaxUint64)
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		switch value {
+		case "NaN", "Infinity", "-Infinity":
+			return true
+		}
+		_, err := strconv.ParseFloat(value, 64)
+		return err == nil
+	}
+	return true
+}
+
+// isInteger accepts exponent forms of integers too, e.g. 1e3.
+func isInteger(value string, lo, hi float64) bool {
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return float64(n) >= lo && float64(n) <= hi
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	return err == nil && f == math.Trunc(f) && f >= lo && f <= hi
+}
+
+func expectedType(fd protoreflect.FieldDescriptor) string {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return "boolean"
+	case protoreflect.StringKind, protoreflect.BytesKind, protoreflect.EnumKind:
+		return "string"
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return "number"
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return "object"
+	}
+	return "integer"
+}
+
+// skip reads the rest of the value started with tok.
+func (c *jsonChecker) skip(tok json.Token, path string) bool {
+	if tok != json.Delim('{') && tok != json.Delim('[') {
+		return true
+	}
+
+	for depth := 1; depth > 0; {
+		next, ok := c.token(path)
+		if !ok {
+			return false
+		}
+		switch next {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+
+	return true
+}
+
+func (c *jsonChecker) add(path, ruleID, expected string) {
+	data := JsonErrorInfo{FieldPath: path, Expected: expected}
+	text, ok := i18n.Default.Render(c.locale, ruleID, data)
+	if !ok {
+		text = ruleID
+	}
+
+	violation := &errorsv1pb.Violation{
+		Field:   path,
+		RuleId:  ruleID,
+		Message: text,
+	}
+	if expected != "" {
+		violation.RuleValue = structpb.NewStringValue(expected)
+	}
+	c.violations = append(c.violations, violation)
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,102 @@
+// Package checks contains ready-made health checks to use with
+// healthcheck.Handler AddLivenessCheck and AddReadinessCheck.
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/pug-go/pug-template/pkg/healthcheck"
+)
+
+// TcpDial checks that a tcp connection to addr (host:port) can be opened.
+func TcpDial(addr string) healthcheck.Check {
+	return func(ctx context.Context) error {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// DnsResolve checks that host resolves to at least one address.
+func DnsResolve(host string) healthcheck.Check {
+	return func(ctx context.Context) error {
+		addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+		if err != nil {
+			return err
+		}
+		if len(addrs) == 0 {
+			return fmt.Errorf("no addresses found for %s", host)
+		}
+		return nil
+	}
+}
+
+// HttpGet checks that GET url responds with expectedStatus. client may be
+// nil to use http.DefaultClient.
+func HttpGet(client *http.Client, url string, expectedStatus int) healthcheck.Check {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != expectedStatus {
+			return fmt.Errorf("unexpected status %d, expected %d", resp.StatusCode, expectedStatus)
+		}
+		return nil
+	}
+}
+
+// GrpcHealth checks that an upstream grpc server reports service as serving
+// via grpc.health.v1.Health, empty service means the whole server.
+func GrpcHealth(conn grpc.ClientConnInterface, service string) healthcheck.Check {
+	client := healthpb.NewHealthClient(conn)
+
+	return func(ctx context.Context) error {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			return err
+		}
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("service %q is %s", service, resp.GetStatus())
+		}
+		return nil
+	}
+}
+
+// SqlPing checks that the database is reachable.
+func SqlPing(db *sql.DB) healthcheck.Check {
+	return db.PingContext
+}
+
+// GoroutineCount fails when the number of goroutines exceeds max, which
+// usually means a goroutine leak.
+func GoroutineCount(max int) healthcheck.Check {
+	return func(_ context.Context) error {
+		if n := runtime.NumGoroutine(); n > max {
+			return fmt.Errorf("too many goroutines: %d, max %d", n, max)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,28 @@
+//go:build linux || darwin || freebsd
+
+package checks
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+
+	"github.com/pug-go/pug-template/pkg/healthcheck"
+)
+
+// DiskFree checks that the filesystem of path has at least minBytes
+// available to unprivileged users.
+func DiskFree(path string, minBytes uint64) healthcheck.Check {
+	return func(_ context.Context) error {
+		var st syscall.Statfs_t
+		if err := syscall.Statfs(path, &st); err != nil {
+			return err
+		}
+
+		free := uint64(st.Bavail) * uint64(st.Bsize) //nolint:unconvert
+		if free < minBytes {
+			return fmt.Errorf("low disk space on %s: %d bytes free, min %d", path, free, minBytes)
+		}
+		return nil
+	}
+}
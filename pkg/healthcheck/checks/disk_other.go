@@ -0,0 +1,17 @@
+//go:build !(linux || darwin || freebsd)
+
+package checks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pug-go/pug-template/pkg/healthcheck"
+)
+
+// DiskFree is not supported on this platform, the check always fails.
+func DiskFree(_ string, _ uint64) healthcheck.Check {
+	return func(_ context.Context) error {
+		return errors.New("disk free check is not supported on this platform")
+	}
+}
@@ -0,0 +1,43 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Heartbeat checks that a background loop succeeded recently. The loop calls
+// Beat after every successful iteration, Check fails if the last beat is
+// older than maxAge:
+//
+//	hb := checks.NewHeartbeat(time.Minute)
+//	app.AddReadinessCheck("consumer", hb.Check)
+type Heartbeat struct {
+	maxAge time.Duration
+	last   atomic.Int64
+}
+
+// NewHeartbeat creates a heartbeat, it's considered fresh for maxAge from
+// creation, so that the first iteration has time to finish.
+func NewHeartbeat(maxAge time.Duration) *Heartbeat {
+	hb := &Heartbeat{
+		maxAge: maxAge,
+	}
+	hb.Beat()
+
+	return hb
+}
+
+// Beat records a successful iteration.
+func (hb *Heartbeat) Beat() {
+	hb.last.Store(time.Now().UnixNano())
+}
+
+func (hb *Heartbeat) Check(_ context.Context) error {
+	age := time.Since(time.Unix(0, hb.last.Load()))
+	if age > hb.maxAge {
+		return fmt.Errorf("last success %s ago, max %s", age.Round(time.Millisecond), hb.maxAge)
+	}
+	return nil
+}
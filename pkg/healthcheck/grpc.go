@@ -0,0 +1,165 @@
+package healthcheck
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// GrpcServiceLiveness, GrpcServiceReadiness and GrpcServiceStartup are
+	// service names to use in kubernetes grpc probes. The empty service name
+	// and every registered service report readiness.
+	GrpcServiceLiveness  = "liveness"
+	GrpcServiceReadiness = "readiness"
+	GrpcServiceStartup   = "startup"
+
+	grpcRefreshInterval = time.Second
+)
+
+// GrpcHealth implements grpc.health.v1.Health on top of Handler checks.
+// Check runs the checks of the requested service only, like the http
+// probes do. Watch gets updates from a background loop refreshing statuses
+// every second while any Watch stream is open.
+type GrpcHealth struct {
+	*health.Server
+	hc       Handler
+	services []string
+	watchers atomic.Int64
+
+	refreshMutex sync.Mutex
+	done         chan struct{}
+	shutdownOnce sync.Once
+}
+
+func NewGrpcHealth(hc Handler) *GrpcHealth {
+	return &GrpcHealth{
+		Server: health.NewServer(),
+		hc:     hc,
+		done:   make(chan struct{}),
+	}
+}
+
+// Register registers the health service on server and starts the refresh
+// loop of Watch streams. It must be called after all other services are
+// registered.
+func (h *GrpcHealth) Register(server *grpc.Server) {
+	for name := range server.GetServiceInfo() {
+		h.services = append(h.services, name)
+	}
+	healthpb.RegisterHealthServer(server, h)
+
+	go h.run()
+}
+
+// Shutdown sets all statuses to NOT_SERVING and ends Watch streams, so that
+// they don't block graceful stop of the grpc server.
+func (h *GrpcHealth) Shutdown() {
+	h.shutdownOnce.Do(func() {
+		h.Server.Shutdown()
+		close(h.done)
+	})
+}
+
+func (h *GrpcHealth) Check(ctx context.Context, in *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	h.refreshMutex.Lock()
+	switch in.GetService() {
+	case GrpcServiceLiveness:
+		h.refreshLiveness()
+	case GrpcServiceStartup:
+		h.refreshStartup()
+	default:
+		h.refreshReadiness()
+	}
+	h.refreshMutex.Unlock()
+
+	return h.Server.Check(ctx, in)
+}
+
+func (h *GrpcHealth) Watch(in *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	// the first status is sent right away, it must be fresh
+	if h.watchers.Add(1) == 1 {
+		h.refresh()
+	}
+	defer h.watchers.Add(-1)
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-h.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return h.Server.Watch(in, &watchStream{Health_WatchServer: stream, ctx: ctx})
+}
+
+func (h *GrpcHealth) run() {
+	ticker := time.NewTicker(grpcRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.done:
+			return
+		case <-ticker.C:
+			if h.watchers.Load() > 0 {
+				h.refresh()
+			}
+		}
+	}
+}
+
+func (h *GrpcHealth) refresh() {
+	h.refreshMutex.Lock()
+	defer h.refreshMutex.Unlock()
+
+	h.refreshLiveness()
+	h.refreshReadiness()
+	h.refreshStartup()
+}
+
+func (h *GrpcHealth) refreshLiveness() {
+	_, live := h.hc.Liveness()
+	h.SetServingStatus(GrpcServiceLiveness, servingStatus(live))
+}
+
+// refreshReadiness sets the status of the empty service name and every
+// registered service too.
+func (h *GrpcHealth) refreshReadiness() {
+	_, ready := h.hc.Readiness()
+	h.SetServingStatus(GrpcServiceReadiness, servingStatus(ready))
+	h.SetServingStatus("", servingStatus(ready))
+	for _, service := range h.services {
+		h.SetServingStatus(service, servingStatus(ready))
+	}
+}
+
+func (h *GrpcHealth) refreshStartup() {
+	_, started := h.hc.Startup()
+	h.SetServingStatus(GrpcServiceStartup, servingStatus(started))
+}
+
+func servingStatus(ok bool) healthpb.HealthCheckResponse_ServingStatus {
+	if ok {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
+
+type watchStream struct {
+	healthpb.Health_WatchServer
+	ctx context.Context
+}
+
+func (s *watchStream) Context() context.Context {
+	return s.ctx
+}
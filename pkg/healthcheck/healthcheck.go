@@ -1,10 +1,15 @@
 package healthcheck
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
+	"time"
+
+	"github.com/pug-go/pug-template/pkg/promlib"
 )
 
 const (
@@ -12,21 +17,47 @@ const (
 
 	CheckHandlerPathLiveness  = "/live"
 	CheckHandlerPathReadiness = "/ready"
+	CheckHandlerPathStartup   = "/startup"
+
+	// DefaultTimeout limits a check run if WithTimeout isn't set.
+	DefaultTimeout = 3 * time.Second
+
+	kindLiveness  = "liveness"
+	kindReadiness = "readiness"
+	kindStartup   = "startup"
+)
+
+// Level is the criticality of a check.
+type Level string
+
+const (
+	// LevelCritical check fails the probe, it's the default.
+	LevelCritical Level = "critical"
+	// LevelWarn check is reported, but doesn't fail the probe.
+	LevelWarn Level = "warn"
 )
 
+var errCheckPending = errors.New("check is pending")
+
 type Handler interface {
 	// AddLivenessCheck adds a check that indicates that this instance of the
 	// application should be destroyed or restarted. A failed liveness check
 	// indicates that this instance is unhealthy, not some upstream dependency.
 	// Every liveness check is also included as a readiness check.
-	AddLivenessCheck(name string, check Check)
+	AddLivenessCheck(name string, check Check, opts ...CheckOption)
 
 	// AddReadinessCheck adds a check that indicates that this instance of the
 	// application is currently unable to serve requests because of an upstream
 	// or some transient failure. If a readiness check fails, this instance
 	// should no longer receiver requests, but should not be restarted or
 	// destroyed.
-	AddReadinessCheck(name string, check Check)
+	AddReadinessCheck(name string, check Check, opts ...CheckOption)
+
+	// AddStartupCheck adds a check that indicates that this instance of the
+	// application has finished starting, e.g. warmed caches or applied
+	// migrations. Once a startup check passes, it's not run anymore. Until
+	// all startup checks have passed, the instance is not ready.
+	AddStartupCheck(name string, check Check, opts ...CheckOption)
 
 	// LiveEndpointHandlerFunc is the HTTP handler for just the /live endpoint, which is
 	// useful if you need to attach it into your own HTTP handler tree.
@@ -35,36 +66,174 @@ type Handler interface {
 	// ReadyEndpointHandlerFunc is the HTTP handler for just the /ready endpoint, which is
 	// useful if you need to attach it into your own HTTP handler tree.
 	ReadyEndpointHandlerFunc(http.ResponseWriter, *http.Request)
+
+	// StartupEndpointHandlerFunc is the HTTP handler for just the /startup endpoint, which is
+	// useful if you need to attach it into your own HTTP handler tree.
+	StartupEndpointHandlerFunc(http.ResponseWriter, *http.Request)
+
+	// Liveness runs liveness checks and returns their results by kind and
+	// name, e.g. liveness/watchdog, and whether all of them passed.
+	Liveness() (results map[string]Result, ok bool)
+
+	// Readiness runs readiness, liveness and startup checks and returns their
+	// results by kind and name, e.g. readiness/db, and whether all of them
+	// passed.
+	Readiness() (results map[string]Result, ok bool)
+
+	// Startup runs startup checks which haven't passed yet and returns their
+	// results by kind and name, e.g. startup/warmup, and whether all of them
+	// passed.
+	Startup() (results map[string]Result, ok bool)
+
+	// Close stops background checks.
+	Close()
+}
+
+// Check is a health/readiness check. It must return when ctx is done.
+type Check func(ctx context.Context) error
+
+// Result of a check run.
+type Result struct {
+	// Result is CheckResultSuccess or the error text.
+	Result    string
+	Level     Level
+	CheckedAt time.Time
+	Duration  time.Duration
+	// LastTransition is when the check last switched between passing and
+	// failing.
+	LastTransition      time.Time
+	ConsecutiveFailures int
+}
+
+// Passed reports whether the check succeeded.
+func (r Result) Passed() bool {
+	return r.Result == CheckResultSuccess
+}
+
+// Age returns how long ago the check was run.
+func (r Result) Age() time.Duration {
+	return time.Since(r.CheckedAt)
+}
+
+type CheckOption func(*checkEntry)
+
+// WithTimeout limits a check run, DefaultTimeout is used otherwise.
+func WithTimeout(timeout time.Duration) CheckOption {
+	return func(e *checkEntry) {
+		e.timeout = timeout
+	}
 }
 
-// Check is a health/readiness check.
-type Check func() error
+// WithLevel sets the check criticality, LevelCritical by default.
+func WithLevel(level Level) CheckOption {
+	return func(e *checkEntry) {
+		e.level = level
+	}
+}
+
+// WithInterval runs the check in background every interval, probes return
+// its last result instead of running it. The check fails until the first
+// run is done.
+func WithInterval(interval time.Duration) CheckOption {
+	return func(e *checkEntry) {
+		e.interval = interval
+	}
+}
+
+// HandlerOption configures a Handler.
+type HandlerOption func(s *basicHandler)
+
+// WithMetrics records check results to metrics instead of promlib.Default.
+func WithMetrics(metrics *promlib.Metrics) HandlerOption {
+	return func(s *basicHandler) {
+		s.metrics = metrics
+	}
+}
 
 // NewHandler creates a new basic Handler
-func NewHandler() Handler {
-	return &basicHandler{
-		livenessChecks:  make(map[string]Check),
-		readinessChecks: make(map[string]Check),
+func NewHandler(opts ...HandlerOption) Handler {
+	s := &basicHandler{
+		metrics:         promlib.Default,
+		livenessChecks:  make(map[string]*checkEntry),
+		readinessChecks: make(map[string]*checkEntry),
+		startupChecks:   make(map[string]*checkEntry),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	return s
 }
 
 // basicHandler is a basic Handler implementation.
 type basicHandler struct {
+	metrics         *promlib.Metrics
 	checksMutex     sync.RWMutex
-	livenessChecks  map[string]Check
-	readinessChecks map[string]Check
+	livenessChecks  map[string]*checkEntry
+	readinessChecks map[string]*checkEntry
+	startupChecks   map[string]*checkEntry
+}
+
+type checkEntry struct {
+	name     string
+	kind     string
+	check    Check
+	level    Level
+	timeout  time.Duration
+	interval time.Duration
+	metrics  *promlib.Metrics
+
+	resultMutex sync.RWMutex
+	result      Result
+	passedOnce  bool
+	stop        context.CancelFunc
 }
 
-func (s *basicHandler) AddLivenessCheck(name string, check Check) {
+func (s *basicHandler) AddLivenessCheck(name string, check Check, opts ...CheckOption) {
+	s.addCheck(s.livenessChecks, kindLiveness, name, check, opts)
+}
+
+func (s *basicHandler) AddReadinessCheck(name string, check Check, opts ...CheckOption) {
+	s.addCheck(s.readinessChecks, kindReadiness, name, check, opts)
+}
+
+func (s *basicHandler) AddStartupCheck(name string, check Check, opts ...CheckOption) {
+	s.addCheck(s.startupChecks, kindStartup, name, check, opts)
+}
+
+func (s *basicHandler) addCheck(checks map[string]*checkEntry, kind, name string, check Check, opts []CheckOption) {
+	entry := &checkEntry{
+		name:    name,
+		kind:    kind,
+		check:   check,
+		level:   LevelCritical,
+		timeout: DefaultTimeout,
+		metrics: s.metrics,
+	}
+	for _, opt := range opts {
+		opt(entry)
+	}
+	if entry.interval > 0 {
+		entry.start()
+	}
+
 	s.checksMutex.Lock()
 	defer s.checksMutex.Unlock()
-	s.livenessChecks[name] = check
+	if prev, ok := checks[name]; ok {
+		prev.close()
+	}
+	checks[name] = entry
 }
 
-func (s *basicHandler) AddReadinessCheck(name string, check Check) {
+func (s *basicHandler) Close() {
 	s.checksMutex.Lock()
 	defer s.checksMutex.Unlock()
-	s.readinessChecks[name] = check
+
+	for _, checkGroup := range []map[string]*checkEntry{s.livenessChecks, s.readinessChecks, s.startupChecks} {
+		for _, entry := range checkGroup {
+			entry.close()
+		}
+	}
 }
 
 func (s *basicHandler) LiveEndpointHandlerFunc(w http.ResponseWriter, r *http.Request) {
@@ -72,26 +241,78 @@ func (s *basicHandler) LiveEndpointHandlerFunc(w http.ResponseWriter, r *http.Re
 }
 
 func (s *basicHandler) ReadyEndpointHandlerFunc(w http.ResponseWriter, r *http.Request) {
-	s.handle(w, r, s.readinessChecks, s.livenessChecks)
+	s.handle(w, r, s.readinessChecks, s.livenessChecks, s.startupChecks)
+}
+
+func (s *basicHandler) StartupEndpointHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	s.handle(w, r, s.startupChecks)
 }
 
-type result struct {
-	name   string
-	result string
+func (s *basicHandler) Liveness() (map[string]Result, bool) {
+	return s.runChecks(s.livenessChecks)
 }
 
-func (s *basicHandler) handle(w http.ResponseWriter, r *http.Request, checkGroups ...map[string]Check) {
+func (s *basicHandler) Readiness() (map[string]Result, bool) {
+	return s.runChecks(s.readinessChecks, s.livenessChecks, s.startupChecks)
+}
+
+func (s *basicHandler) Startup() (map[string]Result, bool) {
+	return s.runChecks(s.startupChecks)
+}
+
+func (s *basicHandler) runChecks(checkGroups ...map[string]*checkEntry) (map[string]Result, bool) {
+	// checks are run unlocked, so that adding a check doesn't wait for them
+	s.checksMutex.RLock()
+	var entries []*checkEntry
+	for _, checkGroup := range checkGroups {
+		for _, entry := range checkGroup {
+			entries = append(entries, entry)
+		}
+	}
+	s.checksMutex.RUnlock()
+
+	results := make([]Result, len(entries))
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = entry.get()
+		}()
+	}
+	wg.Wait()
+
+	checkResults := make(map[string]Result, len(entries))
+	ok := true
+	for i, entry := range entries {
+		checkResults[entry.key()] = results[i]
+		if !results[i].Passed() && results[i].Level != LevelWarn {
+			ok = false
+		}
+	}
+
+	return checkResults, ok
+}
+
+type resultJSON struct {
+	Result              string    `json:"result"`
+	Level               Level     `json:"level"`
+	Age                 string    `json:"age"`
+	Duration            string    `json:"duration"`
+	LastTransition      time.Time `json:"last_transition"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+}
+
+func (s *basicHandler) handle(w http.ResponseWriter, r *http.Request, checkGroups ...map[string]*checkEntry) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	checkResults := make(map[string]string)
+	checkResults, ok := s.runChecks(checkGroups...)
 	status := http.StatusOK
-	for _, checkGroup := range checkGroups {
-		if st := s.collectChecks(checkGroup, checkResults); st != http.StatusOK {
-			status = st
-		}
+	if !ok {
+		status = http.StatusServiceUnavailable
 	}
 
 	// write out the response code and content type header
@@ -109,63 +330,150 @@ func (s *basicHandler) handle(w http.ResponseWriter, r *http.Request, checkGroup
 		return
 	}
 
+	body := make(map[string]resultJSON, len(checkResults))
+	for name, res := range checkResults {
+		body[name] = resultJSON{
+			Result:              res.Result,
+			Level:               res.Level,
+			Age:                 res.Age().Round(time.Millisecond).String(),
+			Duration:            res.Duration.Round(time.Microsecond).String(),
+			LastTransition:      res.LastTransition,
+			ConsecutiveFailures: res.ConsecutiveFailures,
+		}
+	}
+
 	// otherwise, write the JSON body ignoring any encoding errors (which
-	// shouldn't really be possible since we're encoding a map[string]string).
+	// shouldn't really be possible since we're encoding plain structs).
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "    ")
-	_ = encoder.Encode(checkResults)
+	_ = encoder.Encode(body)
 }
 
-func (s *basicHandler) collectChecks(checks map[string]Check, resultsOut map[string]string) (status int) {
-	s.checksMutex.RLock()
-	defer s.checksMutex.RUnlock()
+// key identifies the check in results, names are unique per kind only.
+func (e *checkEntry) key() string {
+	return e.kind + "/" + e.name
+}
 
-	status = http.StatusOK
+// get returns the cached result in background mode or if the startup check
+// has passed, otherwise runs the check.
+func (e *checkEntry) get() Result {
+	e.resultMutex.RLock()
+	cached := e.interval > 0 || (e.kind == kindStartup && e.passedOnce)
+	res := e.result
+	e.resultMutex.RUnlock()
 
-	checkersQty := len(checks)
-	if checkersQty == 0 {
-		return
+	if cached {
+		return res
 	}
+	return e.record(e.run(context.Background()))
+}
+
+// record updates the check state and metrics with a run result.
+func (e *checkEntry) record(res Result) Result {
+	e.resultMutex.Lock()
+	defer e.resultMutex.Unlock()
 
-	results := make(chan result, checkersQty)
+	prev := e.result
+	res.Level = e.level
+	res.LastTransition = prev.LastTransition
+	if prev.CheckedAt.IsZero() || res.Passed() != prev.Passed() {
+		res.LastTransition = res.CheckedAt
+	}
+	if e.kind == kindStartup && e.passedOnce {
+		// startup checks can't fail after they passed
+		return prev
+	}
+	if !res.Passed() {
+		res.ConsecutiveFailures = prev.ConsecutiveFailures + 1
+	}
+	e.result = res
+	e.passedOnce = e.passedOnce || res.Passed()
+
+	labels := []string{e.name, e.kind, string(e.level)}
+	status := 0.0
+	if res.Passed() {
+		status = 1
+	} else {
+		// pug_health_check_failures_total
+		e.metrics.HealthCheckFailuresTotal.WithLabelValues(labels...).Inc()
+	}
+	// pug_health_check_status
+	e.metrics.HealthCheckStatus.WithLabelValues(labels...).Set(status)
+	// pug_health_check_duration_seconds
+	e.metrics.HealthCheckDuration.WithLabelValues(labels...).Set(res.Duration.Seconds())
 
-	// run checks functions and send it to conveyor
-	for name, check := range checks {
-		go func(name string, check Check) {
-			defer func() {
-				if r := recover(); r != nil {
-					results <- result{
-						name:   name,
-						result: fmt.Sprintf("checker panic recovered: %v", r),
-					}
-				}
-			}()
+	return res
+}
 
-			var val = CheckResultSuccess
-			if err := check(); err != nil {
-				val = err.Error()
+// run runs the check with timeout, a check ignoring ctx is abandoned when
+// the timeout expires.
+func (e *checkEntry) run(ctx context.Context) Result {
+	started := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	done := make(chan string, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Sprintf("checker panic recovered: %v", r)
 			}
+		}()
 
-			results <- result{
-				name:   name,
-				result: val,
-			}
-		}(name, check)
+		var val = CheckResultSuccess
+		if err := e.check(ctx); err != nil {
+			val = err.Error()
+		}
+		done <- val
+	}()
+
+	var val string
+	select {
+	case val = <-done:
+	case <-ctx.Done():
+		val = fmt.Sprintf("check timed out after %s", e.timeout)
 	}
 
-	// handle results like conveyor
-	for res := range results {
-		resultsOut[res.name] = res.result
+	return Result{
+		Result:    val,
+		CheckedAt: time.Now(),
+		Duration:  time.Since(started),
+	}
+}
 
-		if res.result != CheckResultSuccess {
-			status = http.StatusServiceUnavailable
-		}
+func (e *checkEntry) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	e.stop = cancel
+	e.result = Result{
+		Result:         errCheckPending.Error(),
+		Level:          e.level,
+		CheckedAt:      time.Now(),
+		LastTransition: time.Now(),
+	}
+
+	go func() {
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+
+		for {
+			res := e.run(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+
+			e.record(res)
 
-		checkersQty--
-		if checkersQty == 0 {
-			close(results)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
 		}
-	}
+	}()
+}
 
-	return status
+func (e *checkEntry) close() {
+	if e.stop != nil {
+		e.stop()
+	}
 }
@@ -0,0 +1,195 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxStackDump limits the goroutine dump written to the log.
+const maxStackDump = 8 << 20
+
+// Watchdog detects stuck loops, e.g. workers, consumers or a deadlocked
+// request path. Every watched loop sends heartbeats, the watchdog check
+// fails when a loop misses its deadline. Use it as liveness check, so that
+// the stuck instance is restarted:
+//
+//	wd := healthcheck.NewWatchdog()
+//	app.AddLivenessCheck("watchdog", wd.Check)
+//
+//	loop := wd.Watch("consumer", time.Minute)
+//	defer loop.Stop()
+//	for msg := range messages {
+//		loop.Beat()
+//		...
+//	}
+//
+// Request paths of servers are watched by WatchActivity.
+//
+// When a loop gets stuck, stacks of all goroutines are dumped to the log
+// once, so that the restart leaves evidence behind.
+type Watchdog struct {
+	mutex sync.Mutex
+	loops map[string]*Loop
+}
+
+// Loop is a watched loop, see Watchdog.
+type Loop struct {
+	name     string
+	deadline time.Duration
+	last     atomic.Int64
+	stuck    atomic.Bool
+	watchdog *Watchdog
+	// idle loops are never stuck, nil means the loop is always busy
+	idle func() bool
+}
+
+// Activity is a watched request path, see Watchdog.WatchActivity.
+type Activity struct {
+	loop   *Loop
+	active atomic.Int64
+}
+
+func NewWatchdog() *Watchdog {
+	return &Watchdog{
+		loops: make(map[string]*Loop),
+	}
+}
+
+// Watch starts watching the loop, it must call Beat at least once per
+// deadline. The deadline is counted from now. Watching a name again
+// replaces the previous loop.
+func (w *Watchdog) Watch(name string, deadline time.Duration) *Loop {
+	l := &Loop{
+		name:     name,
+		deadline: deadline,
+		watchdog: w,
+	}
+	l.Beat()
+
+	w.mutex.Lock()
+	w.loops[name] = l
+	w.mutex.Unlock()
+
+	return l
+}
+
+// WatchActivity starts watching a request path, e.g. handlers of a server.
+// It's stuck when requests are in progress, but none of them finished for
+// the deadline, so the deadline must be longer than the slowest request.
+// An idle path is never stuck.
+func (w *Watchdog) WatchActivity(name string, deadline time.Duration) *Activity {
+	a := &Activity{}
+	a.loop = w.Watch(name, deadline)
+	a.loop.idle = func() bool {
+		return a.active.Load() == 0
+	}
+	return a
+}
+
+// Start reports a started request, done must be called when it finishes.
+// Nil Activity is not watched.
+func (a *Activity) Start() (done func()) {
+	if a == nil {
+		return func() {}
+	}
+	// deadline of the first request is counted from its start, later
+	// starts are not a progress: they go on when handlers are stuck. It
+	// beats before the loop becomes active, so that Check doesn't see an
+	// active loop with the heartbeat of the idle time.
+	if a.active.Load() == 0 {
+		a.loop.Beat()
+	}
+	a.active.Add(1)
+	return func() {
+		a.loop.Beat()
+		a.active.Add(-1)
+	}
+}
+
+// Stop ends watching the request path.
+func (a *Activity) Stop() {
+	if a != nil {
+		a.loop.Stop()
+	}
+}
+
+// Beat reports that the loop is alive.
+func (l *Loop) Beat() {
+	l.last.Store(time.Now().UnixNano())
+}
+
+// Stop ends watching the loop, e.g. when it returns normally.
+func (l *Loop) Stop() {
+	l.watchdog.mutex.Lock()
+	defer l.watchdog.mutex.Unlock()
+
+	if l.watchdog.loops[l.name] == l {
+		delete(l.watchdog.loops, l.name)
+	}
+}
+
+// Check fails if any loop missed its deadline. It never waits on locks
+// held by the watched code, so it keeps working when the app deadlocks.
+func (w *Watchdog) Check(_ context.Context) error {
+	w.mutex.Lock()
+	loops := make([]*Loop, 0, len(w.loops))
+	for _, l := range w.loops {
+		loops = append(loops, l)
+	}
+	w.mutex.Unlock()
+
+	sort.Slice(loops, func(i, j int) bool {
+		return loops[i].name < loops[j].name
+	})
+
+	var errs []error
+	var newlyStuck []string
+	for _, l := range loops {
+		age := time.Since(time.Unix(0, l.last.Load()))
+		if age <= l.deadline || (l.idle != nil && l.idle()) {
+			l.stuck.Store(false)
+			continue
+		}
+
+		errs = append(errs, fmt.Errorf("loop %q: last heartbeat %s ago, deadline %s",
+			l.name, age.Round(time.Millisecond), l.deadline))
+		if !l.stuck.Swap(true) {
+			newlyStuck = append(newlyStuck, l.name)
+		}
+	}
+
+	if len(newlyStuck) > 0 {
+		dumpStacks(newlyStuck)
+	}
+
+	return errors.Join(errs...)
+}
+
+// dumpStacks logs stacks of all goroutines.
+func dumpStacks(stuck []string) {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxStackDump {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	// single entry, so that log pipelines keep the dump together
+	log.WithFields(log.Fields{
+		"loops":      strings.Join(stuck, ","),
+		"goroutines": runtime.NumGoroutine(),
+		"stacks":     string(buf),
+	}).Error("watchdog: loops missed deadline")
+}
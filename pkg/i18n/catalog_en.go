@@ -0,0 +1,165 @@
+package i18n
+
+// catalogEn is the built in English catalog.
+var catalogEn = Catalog{
+	"float.const":  "value must equal {{.RuleValue}}",
+	"float.in":     "value must be in list {{.RuleValue}}",
+	"float.not_in": "value must not be in list {{.RuleValue}}",
+	"float.finite": "value {{.FieldValue}} must be finite",
+
+	"double.const":  "value must equal {{.RuleValue}}",
+	"double.in":     "value must be in list {{.RuleValue}}",
+	"double.not_in": "value must not be in list {{.RuleValue}}",
+	"double.finite": "value {{.FieldValue}} must be finite",
+
+	"int32.const":  "value must equal {{.RuleValue}}",
+	"int32.in":     "value must be in list {{.RuleValue}}",
+	"int32.not_in": "value must not be in list {{.RuleValue}}",
+
+	"int64.const":  "value must equal {{.RuleValue}}",
+	"int64.in":     "value must be in list {{.RuleValue}}",
+	"int64.not_in": "value must not be in list {{.RuleValue}}",
+
+	"uint32.const":  "value must equal {{.RuleValue}}",
+	"uint32.in":     "value must be in list {{.RuleValue}}",
+	"uint32.not_in": "value must not be in list {{.RuleValue}}",
+
+	"uint64.const":  "value must equal {{.RuleValue}}",
+	"uint64.in":     "value must be in list {{.RuleValue}}",
+	"uint64.not_in": "value must not be in list {{.RuleValue}}",
+
+	"sint32.const":  "value must equal {{.RuleValue}}",
+	"sint32.in":     "value must be in list {{.RuleValue}}",
+	"sint32.not_in": "value must not be in list {{.RuleValue}}",
+
+	"sint64.const":  "value must equal {{.RuleValue}}",
+	"sint64.in":     "value must be in list {{.RuleValue}}",
+	"sint64.not_in": "value must not be in list {{.RuleValue}}",
+
+	"fixed32.const":  "value must equal {{.RuleValue}}",
+	"fixed32.in":     "value must be in list {{.RuleValue}}",
+	"fixed32.not_in": "value must not be in list {{.RuleValue}}",
+
+	"fixed64.const":  "value must equal {{.RuleValue}}",
+	"fixed64.in":     "value must be in list {{.RuleValue}}",
+	"fixed64.not_in": "value must not be in list {{.RuleValue}}",
+
+	"sfixed32.const":  "value must equal {{.RuleValue}}",
+	"sfixed32.in":     "value must be in list {{.RuleValue}}",
+	"sfixed32.not_in": "value must not be in list {{.RuleValue}}",
+
+	"sfixed64.const":  "value must equal {{.RuleValue}}",
+	"sfixed64.in":     "value must be in list {{.RuleValue}}",
+	"sfixed64.not_in": "value must not be in list {{.RuleValue}}",
+
+	"bool.const": "value must equal {{.RuleValue}}",
+
+	"string.const":        "value must equal \"{{.RuleValue}}\"",
+	"string.len":          "value length must be {{.RuleValue}} characters",
+	"string.min_len":      "value length must be at least {{.RuleValue}} characters",
+	"string.max_len":      "value length must be at most {{.RuleValue}} characters",
+	"string.len_bytes":    "value length must be {{.RuleValue}} bytes",
+	"string.min_bytes":    "value length must be at least {{.RuleValue}} bytes",
+	"string.max_bytes":    "value length must be at most {{.RuleValue}} bytes",
+	"string.pattern":      "value does not match regex pattern \"{{.RuleValue}}\"",
+	"string.prefix":       "value does not have prefix \"{{.RuleValue}}\"",
+	"string.suffix":       "value does not have suffix \"{{.RuleValue}}\"",
+	"string.contains":     "value does not contain substring \"{{.RuleValue}}\"",
+	"string.not_contains": "value contains substring \"{{.RuleValue}}\"",
+	"string.in":           "value must be in list {{.RuleValue}}",
+	"string.not_in":       "value must not be in list {{.RuleValue}}",
+
+	"string.email":                              "value must be a valid email address",
+	"string.email_empty":                        "value is empty, which is not a valid email address",
+	"string.hostname":                           "value must be a valid hostname",
+	"string.hostname_empty":                     "value is empty, which is not a valid hostname",
+	"string.ip":                                 "value must be a valid IP address",
+	"string.ip_empty":                           "value is empty, which is not a valid IP address",
+	"string.ipv4":                               "value must be a valid IPv4 address",
+	"string.ipv4_empty":                         "value is empty, which is not a valid IPv4 address",
+	"string.ipv6":                               "value must be a valid IPv6 address",
+	"string.ipv6_empty":                         "value is empty, which is not a valid IPv6 address",
+	"string.uri":                                "value must be a valid URI",
+	"string.uri_empty":                          "value is empty, which is not a valid URI",
+	"string.uri_ref":                            "value must be a valid URI reference",
+	"string.address":                            "value must be a valid hostname or IP address",
+	"string.address_empty":                      "value is empty, which is not a valid hostname or IP address",
+	"string.uuid":                               "value must be a valid UUID",
+	"string.uuid_empty":                         "value is empty, which is not a valid UUID",
+	"string.tuuid":                              "value must be a valid trimmed UUID",
+	"string.tuuid_empty":                        "value is empty, which is not a valid trimmed UUID",
+	"string.ip_with_prefixlen":                  "value must be a valid IP with prefix length",
+	"string.ip_with_prefixlen_empty":            "value is empty, which is not a valid IP with prefix length",
+	"string.ipv4_with_prefixlen":                "value must be a valid IPv4 with prefix length",
+	"string.ipv4_with_prefixlen_empty":          "value is empty, which is not a valid IPv4 with prefix length",
+	"string.ipv6_with_prefixlen":                "value must be a valid IPv6 with prefix length",
+	"string.ipv6_with_prefixlen_empty":          "value is empty, which is not a valid IPv6 with prefix length",
+	"string.ip_prefix":                          "value must be a valid IP prefix",
+	"string.ip_prefix_empty":                    "value is empty, which is not a valid IP prefix",
+	"string.ipv4_prefix":                        "value must be a valid IPv4 prefix",
+	"string.ipv4_prefix_empty":                  "value is empty, which is not a valid IPv4 prefix",
+	"string.ipv6_prefix":                        "value must be a valid IPv6 prefix",
+	"string.ipv6_prefix_empty":                  "value is empty, which is not a valid IPv6 prefix",
+	"string.host_and_port":                      "value must be a valid host and port pair",
+	"string.host_and_port_empty":                "value is empty, which is not a valid host and port pair",
+	"string.well_known_regex.header_name":       "value must be a valid HTTP header name",
+	"string.well_known_regex.header_name_empty": "value is empty, which is not a valid HTTP header name",
+	"string.well_known_regex.header_value":      "value must be a valid HTTP header value",
+
+	"bytes.const":      "value must equal {{.RuleValue}}",
+	"bytes.len":        "value length must be {{.RuleValue}} bytes",
+	"bytes.min_len":    "value length must be at least {{.RuleValue}} bytes",
+	"bytes.max_len":    "value length must be at most {{.RuleValue}} bytes",
+	"bytes.pattern":    "value must match regex pattern \"{{.RuleValue}}\"",
+	"bytes.prefix":     "value does not have prefix {{.RuleValue}}",
+	"bytes.suffix":     "value does not have suffix {{.RuleValue}}",
+	"bytes.contains":   "value does not contain {{.RuleValue}}",
+	"bytes.in":         "value must be in list {{.RuleValue}}",
+	"bytes.not_in":     "value must not be in list {{.RuleValue}}",
+	"bytes.ip":         "value must be a valid IP address",
+	"bytes.ip_empty":   "value is empty, which is not a valid IP address",
+	"bytes.ipv4":       "value must be a valid IPv4 address",
+	"bytes.ipv4_empty": "value is empty, which is not a valid IPv4 address",
+	"bytes.ipv6":       "value must be a valid IPv6 address",
+	"bytes.ipv6_empty": "value is empty, which is not a valid IPv6 address",
+
+	"enum.const":  "value must equal {{.RuleValue}}",
+	"enum.in":     "value must be in list {{.RuleValue}}",
+	"enum.not_in": "value must not be in list {{.RuleValue}}",
+
+	"repeated.min_items": "value must contain at least {{.RuleValue}} item(s)",
+	"repeated.max_items": "value must contain no more than {{.RuleValue}} item(s)",
+	"repeated.unique":    "repeated value must contain unique items",
+
+	"map.min_pairs": "map must be at least {{.RuleValue}} entries",
+	"map.max_pairs": "map must be at most {{.RuleValue}} entries",
+
+	"duration.const":  "value must equal {{.RuleValue}}",
+	"duration.in":     "value must be in list {{.RuleValue}}",
+	"duration.not_in": "value must not be in list {{.RuleValue}}",
+
+	"timestamp.const":  "value must equal {{.RuleValue}}",
+	"timestamp.lt_now": "value must be less than now",
+	"timestamp.gt_now": "value must be greater than now",
+	"timestamp.within": "value must be within {{.RuleValue}} of now",
+
+	"json.syntax":        "malformed JSON",
+	"json.unknown_field": "unknown field",
+	"json.duplicate_key": "field is set more than once",
+	"json.type":          "value must be {{.Expected}}",
+	"json.enum":          "unknown enum value",
+
+	"http.400": "Bad Request",
+	"http.401": "Unauthorized",
+	"http.403": "Forbidden",
+	"http.404": "Not Found",
+	"http.408": "Request Timeout",
+	"http.409": "Conflict",
+	"http.412": "Precondition Failed",
+	"http.429": "Too Many Requests",
+	"http.499": "Client Closed Request",
+	"http.500": "Internal Server Error",
+	"http.501": "Not Implemented",
+	"http.503": "Service Unavailable",
+	"http.504": "Gateway Timeout",
+}
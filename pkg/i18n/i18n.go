@@ -0,0 +1,236 @@
+package i18n
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// MetadataKey is the grpc metadata key of the requested locales, it has the
+// Accept-Language header format and is forwarded by the gateway.
+const MetadataKey = "accept-language"
+
+// locales of the built in catalogs
+//
+//goland:noinspection GoSnakeCaseUsage
+const (
+	Locale_En = "en"
+	Locale_Ru = "ru"
+)
+
+// Catalog maps message keys to text/template templates of one locale. Keys
+// are validation rule IDs, e.g. string.min_len or a custom CEL rule ID,
+// json.<error> for the gateway strict json errors and http.<code> for the
+// gateway generic texts.
+type Catalog map[string]string
+
+// Bundle holds catalogs of all locales, it's safe for concurrent use.
+type Bundle struct {
+	mutex         sync.RWMutex
+	defaultLocale string
+	templates     map[string]map[string]*template.Template
+}
+
+// Default bundle has the built in en and ru catalogs, ru is the default
+// locale.
+var Default = NewBundle(Locale_Ru)
+
+// NewBundle creates a bundle with the built in catalogs, defaultLocale is
+// used when no requested locale is supported.
+func NewBundle(defaultLocale string) *Bundle {
+	b := &Bundle{
+		defaultLocale: Normalize(defaultLocale),
+		templates:     make(map[string]map[string]*template.Template),
+	}
+	for locale, catalog := range map[string]Catalog{Locale_En: catalogEn, Locale_Ru: catalogRu} {
+		if err := b.Register(locale, catalog); err != nil {
+			panic(err)
+		}
+	}
+
+	return b
+}
+
+// Register adds templates of the catalog to the locale, existing templates
+// with the same keys are overridden. Nothing is added if any template is
+// invalid.
+func (b *Bundle) Register(locale string, catalog Catalog) error {
+	parsed := make(map[string]*template.Template, len(catalog))
+	for key, text := range catalog {
+		t, err := template.New(key).Parse(text)
+		if err != nil {
+			return fmt.Errorf("i18n: %s: %s: %w", locale, key, err)
+		}
+		parsed[key] = t
+	}
+
+	locale = Normalize(locale)
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.templates[locale] == nil {
+		b.templates[locale] = make(map[string]*template.Template, len(parsed))
+	}
+	for key, t := range parsed {
+		b.templates[locale][key] = t
+	}
+
+	return nil
+}
+
+// Set overrides a single template of the locale.
+func (b *Bundle) Set(locale, key, text string) error {
+	return b.Register(locale, Catalog{key: text})
+}
+
+// SetDefaultLocale sets the locale used when no requested one is supported.
+func (b *Bundle) SetDefaultLocale(locale string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.defaultLocale = Normalize(locale)
+}
+
+// Locales returns the registered locales.
+func (b *Bundle) Locales() []string {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	locales := make([]string, 0, len(b.templates))
+	for locale := range b.templates {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	return locales
+}
+
+// Negotiate picks the best registered locale for the Accept-Language value,
+// e.g. "ru-RU,ru;q=0.9,en;q=0.8". A region falls back to its language, the
+// default locale is returned if nothing matches.
+func (b *Bundle) Negotiate(acceptLanguage string) string {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		if _, ok := b.templates[tag]; ok {
+			return tag
+		}
+		if base, _, ok := strings.Cut(tag, "-"); ok {
+			if _, ok = b.templates[base]; ok {
+				return base
+			}
+		}
+	}
+
+	return b.defaultLocale
+}
+
+// Render executes the template of the key in the locale, falling back to the
+// base language and the default locale. ok is false if no template is found.
+func (b *Bundle) Render(locale, key string, data any) (text string, ok bool) {
+	t := b.lookup(locale, key)
+	if t == nil {
+		return "", false
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", false
+	}
+
+	return buf.String(), true
+}
+
+func (b *Bundle) lookup(locale, key string) *template.Template {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for _, l := range append(Fallbacks(locale), b.defaultLocale) {
+		if t, ok := b.templates[l][key]; ok {
+			return t
+		}
+	}
+
+	return nil
+}
+
+// Register adds the catalog to the Default bundle, see Bundle.Register.
+func Register(locale string, catalog Catalog) error {
+	return Default.Register(locale, catalog)
+}
+
+// Set overrides a template of the Default bundle, see Bundle.Set.
+func Set(locale, key, text string) error {
+	return Default.Set(locale, key, text)
+}
+
+type localeKey struct{}
+
+// WithLocale returns ctx carrying the negotiated locale.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the locale set by WithLocale, empty if none.
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+// parseAcceptLanguage returns normalized tags ordered by quality.
+func parseAcceptLanguage(value string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(value, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = Normalize(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil || parsed <= 0 {
+				continue
+			}
+			q = parsed
+		}
+		tags = append(tags, weighted{tag: tag, q: q})
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+
+	return result
+}
+
+// Fallbacks returns the normalized locale and its base language: ru-RU
+// falls back to ru.
+func Fallbacks(locale string) []string {
+	locale = Normalize(locale)
+	if base, _, ok := strings.Cut(locale, "-"); ok {
+		return []string{locale, base}
+	}
+	return []string{locale}
+}
+
+// Normalize lowercases the locale tag and uses "-" as separator: ru_RU is
+// ru-ru.
+func Normalize(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+}
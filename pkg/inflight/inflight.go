@@ -0,0 +1,80 @@
+package inflight
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// Counter tracks the number of requests currently being served, so that
+// shutdown can wait for them to finish before stopping the servers.
+type Counter struct {
+	mu   sync.Mutex
+	n    int64
+	idle chan struct{}
+}
+
+func New() *Counter {
+	idle := make(chan struct{})
+	close(idle)
+
+	return &Counter{idle: idle}
+}
+
+// Acquire marks the start of a request.
+func (c *Counter) Acquire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.n == 0 {
+		c.idle = make(chan struct{})
+	}
+	c.n++
+}
+
+// Release marks the end of a request started with Acquire.
+func (c *Counter) Release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.n--
+	if c.n == 0 {
+		close(c.idle)
+	}
+}
+
+// Count returns the number of requests in flight.
+func (c *Counter) Count() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}
+
+// Wait blocks until there are no requests in flight or ctx is done.
+func (c *Counter) Wait(ctx context.Context) error {
+	for {
+		c.mu.Lock()
+		if c.n == 0 {
+			c.mu.Unlock()
+			return nil
+		}
+		idle := c.idle
+		c.mu.Unlock()
+
+		select {
+		case <-idle:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Middleware counts every http request passing through it.
+func (c *Counter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Acquire()
+		defer c.Release()
+
+		next.ServeHTTP(w, r)
+	})
+}
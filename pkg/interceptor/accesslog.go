@@ -0,0 +1,131 @@
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pug-go/pug-template/pkg/accesslog"
+	"github.com/pug-go/pug-template/pkg/gwopts"
+	"github.com/pug-go/pug-template/pkg/requestid"
+)
+
+// UnaryServerAccessLog writes a record of the call to logger, nil logger
+// disables it. Calls of the gateway are logged by the http middleware, they
+// are logged here too only with payloads of the method enabled.
+func UnaryServerAccessLog(logger *accesslog.Logger) func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !logger.Enabled() {
+			return handler(ctx, req)
+		}
+
+		started := time.Now()
+		resp, err := handler(ctx, req)
+
+		payload := logger.LogPayload(info.FullMethod)
+		grpcGateway := gwopts.IsFromGateway(ctx)
+		if grpcGateway && !payload {
+			return resp, err
+		}
+		if !logger.Sampled(err != nil) {
+			return resp, err
+		}
+
+		fields := accessLogFields(ctx, info.FullMethod, started, err)
+		fields["request_size"] = messageSize(req)
+		fields["response_size"] = messageSize(resp)
+		if grpcGateway {
+			fields["gateway"] = true
+		}
+		if payload {
+			fields["request"] = logger.Payload(req)
+			if err == nil {
+				fields["response"] = logger.Payload(resp)
+			}
+		}
+		logger.Log(fields)
+
+		return resp, err
+	}
+}
+
+// StreamServerAccessLog writes a record of the stream to logger when it
+// ends, sizes are sums of the messages, payloads are not logged.
+func StreamServerAccessLog(logger *accesslog.Logger) func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !logger.Enabled() || gwopts.IsFromGateway(ss.Context()) {
+			return handler(srv, ss)
+		}
+
+		started := time.Now()
+		stream := &sizedServerStream{ServerStream: ss}
+		err := handler(srv, stream)
+
+		if !logger.Sampled(err != nil) {
+			return err
+		}
+
+		fields := accessLogFields(ss.Context(), info.FullMethod, started, err)
+		fields["request_size"] = stream.received
+		fields["response_size"] = stream.sent
+		logger.Log(fields)
+
+		return err
+	}
+}
+
+// sizedServerStream sums sizes of the stream messages.
+type sizedServerStream struct {
+	grpc.ServerStream
+	received int
+	sent     int
+}
+
+func (s *sizedServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent += messageSize(m)
+	}
+	return err
+}
+
+func (s *sizedServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received += messageSize(m)
+	}
+	return err
+}
+
+func accessLogFields(ctx context.Context, fullMethod string, started time.Time, err error) log.Fields {
+	fields := log.Fields{
+		"protocol":    "grpc",
+		"method":      fullMethod,
+		"code":        status.Code(err).String(),
+		"duration_ms": float64(time.Since(started).Microseconds()) / 1000,
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields["peer"] = p.Addr.String()
+	}
+	if id := requestid.FromContext(ctx); id != "" {
+		fields["request_id"] = id
+	}
+	if source := requestid.SourceFromContext(ctx); source != "" {
+		fields["source"] = source
+	}
+
+	return fields
+}
+
+func messageSize(m interface{}) int {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return 0
+	}
+	return proto.Size(msg)
+}
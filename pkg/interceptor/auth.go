@@ -0,0 +1,102 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/pug-go/pug-template/pkg/auth"
+	"github.com/pug-go/pug-template/pkg/tlsconf"
+)
+
+// UnaryServerAuth verifies the bearer token of the authorization metadata
+// and puts its claims into the context, see auth.ClaimsFromContext. The
+// gateway forwards the Authorization header as this metadata. Methods are
+// checked by their policy, see auth.MethodPolicy: public methods may be
+// called without a token, internal ones only with a verified client
+// certificate, see UnaryServerIdentity. Then the authorization option of the method is
+// checked, see auth.Authorize. Nil verifier disables auth.
+func UnaryServerAuth(verifier *auth.Verifier) func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if verifier == nil {
+			return handler(ctx, req)
+		}
+
+		ctx, err := authenticate(ctx, verifier, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func StreamServerAuth(verifier *auth.Verifier) func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if verifier == nil {
+			return handler(srv, ss)
+		}
+
+		ctx, err := authenticate(ss.Context(), verifier, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextServerStream{
+			ServerStream: ss,
+			ctx:          ctx,
+		})
+	}
+}
+
+// authenticate returns the context with claims of a valid token. Errors
+// of the authenticated policy have the WWW-Authenticate challenge as
+// message, the gateway responds it as the header.
+func authenticate(ctx context.Context, verifier *auth.Verifier, fullMethod string) (context.Context, error) {
+	policy := auth.MethodPolicy(fullMethod, verifier.DefaultPolicy())
+
+	if policy == auth.Policy_Internal {
+		if _, ok := tlsconf.IdentityFromContext(ctx); !ok {
+			return ctx, status.Error(codes.PermissionDenied, "verified client certificate is required")
+		}
+	}
+
+	claims, err := verifyToken(ctx, verifier)
+	if err == nil {
+		return auth.WithClaims(ctx, claims), nil
+	}
+
+	switch policy {
+	case auth.Policy_Authenticated:
+		if errors.Is(err, errMissingToken) {
+			return ctx, status.Error(codes.Unauthenticated, auth.Challenge(nil))
+		}
+		return ctx, status.Error(codes.Unauthenticated, auth.Challenge(err))
+	case auth.Policy_Public:
+		if !errors.Is(err, errMissingToken) {
+			// anonymous access is allowed, the token is ignored
+			log.Debugf("auth: invalid token on public method %s: %s", fullMethod, err)
+		}
+	}
+
+	return ctx, nil
+}
+
+var errMissingToken = errors.New("missing bearer token")
+
+func verifyToken(ctx context.Context, verifier *auth.Verifier) (*auth.Claims, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	value := lastValue(md, auth.MetadataKey)
+	if value == "" {
+		return nil, errMissingToken
+	}
+
+	token, err := auth.BearerToken(value)
+	if err != nil {
+		return nil, err
+	}
+	return verifier.Verify(ctx, token)
+}
@@ -0,0 +1,59 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	errorsv1pb "github.com/pug-go/pug-template/gen/pug/errors/v1"
+	"github.com/pug-go/pug-template/pkg/auth"
+)
+
+// UnaryServerAuthorization checks the authorization option of the method
+// against the claims and the identity of the caller, see auth.Authorize.
+// It runs even if auth is disabled, then only identities are granted, so
+// that methods requiring roles aren't open. It must be chained after
+// UnaryServerIdentity and UnaryServerAuth.
+func UnaryServerAuthorization() func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func StreamServerAuthorization() func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// authorize returns PermissionDenied with errorsv1pb.Denial detail if the
+// caller isn't granted what the method requires.
+func authorize(ctx context.Context, fullMethod string) error {
+	err := auth.Authorize(ctx, fullMethod)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, auth.ErrNoCredentials) {
+		return status.Error(codes.Unauthenticated, auth.Challenge(nil))
+	}
+
+	var denied *auth.Denied
+	if !errors.As(err, &denied) {
+		return status.Error(codes.Internal, err.Error())
+	}
+	st, derr := status.New(codes.PermissionDenied, denied.Error()).
+		WithDetails(&errorsv1pb.Denial{Reason: denied.Reason, Required: denied.Required})
+	if derr != nil {
+		return status.Error(codes.PermissionDenied, denied.Error())
+	}
+	return st.Err()
+}
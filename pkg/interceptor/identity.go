@@ -0,0 +1,83 @@
+package interceptor
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/pug-go/pug-template/pkg/listener"
+	"github.com/pug-go/pug-template/pkg/tlsconf"
+)
+
+// UnaryServerIdentity puts the caller identity from the client certificate
+// into the context, see tlsconf.Reloader.PeerIdentity. For requests of the
+// internal http gateway the certificate of the original http client is used,
+// the gateway forwards only verified ones. own is the tls of connections of
+// callers, may be nil. The gateway is trusted only in-process or when it
+// presents own certificate, so http client identities reach grpc over plain
+// text only in single port mode.
+func UnaryServerIdentity(own *tlsconf.Reloader) func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(withIdentity(ctx, own), req)
+	}
+}
+
+func StreamServerIdentity(own *tlsconf.Reloader) func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextServerStream{
+			ServerStream: ss,
+			ctx:          withIdentity(ss.Context(), own),
+		})
+	}
+}
+
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func withIdentity(ctx context.Context, own *tlsconf.Reloader) context.Context {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	// in single port mode gateway calls grpc in-process
+	internal := p.Addr != nil && p.Addr.Network() == listener.Network_Pipe
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if ok && len(tlsInfo.State.PeerCertificates) > 0 {
+		if !own.IsOwnCertificate(tlsInfo.State.PeerCertificates[0].Raw) {
+			if id := own.PeerIdentity(&tlsInfo.State); id != nil {
+				return tlsconf.WithIdentity(ctx, id)
+			}
+			return ctx
+		}
+		internal = true
+	}
+	if !internal {
+		return ctx
+	}
+
+	// gateway always sets the header last, client values are ignored
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(tlsconf.ForwardedCertHeader)
+	if len(values) == 0 || values[len(values)-1] == "" {
+		return ctx
+	}
+
+	cert, err := tlsconf.DecodeCert(values[len(values)-1])
+	if err != nil {
+		log.Errorf("identity: invalid forwarded certificate: %s", err)
+		return ctx
+	}
+	return tlsconf.WithIdentity(ctx, tlsconf.NewIdentity(cert))
+}
@@ -0,0 +1,27 @@
+package interceptor
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/pug-go/pug-template/pkg/inflight"
+)
+
+func UnaryServerInflight(counter *inflight.Counter) func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		counter.Acquire()
+		defer counter.Release()
+
+		return handler(ctx, req)
+	}
+}
+
+func StreamServerInflight(counter *inflight.Counter) func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		counter.Acquire()
+		defer counter.Release()
+
+		return handler(srv, ss)
+	}
+}
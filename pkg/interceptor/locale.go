@@ -0,0 +1,55 @@
+package interceptor
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/pug-go/pug-template/pkg/i18n"
+)
+
+// UnaryServerLocale puts the locale negotiated from the accept-language
+// metadata into the context, see i18n.LocaleFromContext. The gateway
+// forwards the Accept-Language header as this metadata. Nil bundle means
+// i18n.Default.
+func UnaryServerLocale(bundle *i18n.Bundle) func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if bundle == nil {
+		bundle = i18n.Default
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(i18n.WithLocale(ctx, negotiateLocale(ctx, bundle)), req)
+	}
+}
+
+func StreamServerLocale(bundle *i18n.Bundle) func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if bundle == nil {
+		bundle = i18n.Default
+	}
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextServerStream{
+			ServerStream: ss,
+			ctx:          i18n.WithLocale(ss.Context(), negotiateLocale(ss.Context(), bundle)),
+		})
+	}
+}
+
+// callerLocale returns the locale set by the locale interceptor, or
+// negotiates it if the interceptor isn't used.
+func callerLocale(ctx context.Context, bundle *i18n.Bundle) string {
+	if locale := i18n.LocaleFromContext(ctx); locale != "" {
+		return locale
+	}
+	return negotiateLocale(ctx, bundle)
+}
+
+func negotiateLocale(ctx context.Context, bundle *i18n.Bundle) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(i18n.MetadataKey)
+	if len(values) == 0 {
+		return bundle.Negotiate("")
+	}
+	return bundle.Negotiate(values[len(values)-1])
+}
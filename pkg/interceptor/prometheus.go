@@ -4,78 +4,132 @@ import (
 	"context"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/metadata"
 
+	"github.com/pug-go/pug-template/pkg/gwopts"
 	"github.com/pug-go/pug-template/pkg/promlib"
 )
 
-func UnaryServerPrometheus() func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+// UnaryServerPrometheus records metrics of the call and its request and
+// response messages to metrics, nil means promlib.Default.
+func UnaryServerPrometheus(metrics *promlib.Metrics) func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if metrics == nil {
+		metrics = promlib.Default
+	}
+
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		started := time.Now()
+		method := promlib.GetGrpcHandlerName(info.FullMethod)
+
+		// pug_grpc_messages_received_total
+		metrics.GrpcMessagesReceivedTotal.WithLabelValues(method, promlib.GrpcType_Unary).Inc()
 
 		resp, err := handler(ctx, req)
 
-		// ignore internal grpc-gateway http requests
-		grpcGateway := isFromGrpcGateway(ctx)
+		if err == nil {
+			// pug_grpc_messages_sent_total
+			metrics.GrpcMessagesSentTotal.WithLabelValues(method, promlib.GrpcType_Unary).Inc()
+		}
+
+		// ignore internal grpc-gateway http requests, they are recorded by
+		// the http middleware
+		grpcGateway := gwopts.IsFromGateway(ctx)
 		if grpcGateway {
 			return resp, err
 		}
 
-		method := promlib.GetGrpcHandlerName(info.FullMethod)
 		status := promlib.GrpcErrorToStatus(err)
 
-		handleMetrics(started, method, status)
+		handleMetrics(metrics, started, method, status)
 
 		return resp, err
 	}
 }
 
-func StreamServerPrometheus() func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+// StreamServerPrometheus records metrics of the stream, its lifetime and
+// messages to metrics, nil means promlib.Default. Streams of the gateway are
+// served too, only their request metrics are left to the http middleware.
+func StreamServerPrometheus(metrics *promlib.Metrics) func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if metrics == nil {
+		metrics = promlib.Default
+	}
+
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		started := time.Now()
-		ctx := ss.Context()
+		method := promlib.GetGrpcHandlerName(info.FullMethod)
+		grpcType := promlib.GetGrpcType(info)
 
-		// ignore internal grpc-gateway http requests
-		grpcGateway := isFromGrpcGateway(ctx)
-		if grpcGateway {
-			return nil
-		}
+		// pug_grpc_streams_active
+		active := metrics.GrpcStreamsActive.WithLabelValues(method, grpcType)
+		active.Inc()
+		defer active.Dec()
 
-		err := handler(srv, ss)
+		err := handler(srv, &monitoredServerStream{
+			ServerStream: ss,
+			received:     metrics.GrpcMessagesReceivedTotal.WithLabelValues(method, grpcType),
+			sent:         metrics.GrpcMessagesSentTotal.WithLabelValues(method, grpcType),
+		})
 
-		method := promlib.GetGrpcHandlerName(info.FullMethod)
 		status := promlib.GrpcErrorToStatus(err)
 
-		handleMetrics(started, method, status)
+		// pug_grpc_stream_duration_seconds
+		metrics.GrpcStreamDuration.WithLabelValues(
+			method,
+			grpcType,
+			status,
+		).Observe(promlib.CalculateObservation(started))
+
+		// ignore internal grpc-gateway http requests, they are recorded by
+		// the http middleware
+		grpcGateway := gwopts.IsFromGateway(ss.Context())
+		if grpcGateway {
+			return err
+		}
+
+		handleMetrics(metrics, started, method, status)
 
 		return err
 	}
 }
 
-func handleMetrics(started time.Time, method, status string) {
+// monitoredServerStream counts messages of the stream.
+type monitoredServerStream struct {
+	grpc.ServerStream
+	received prometheus.Counter
+	sent     prometheus.Counter
+}
+
+func (s *monitoredServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		// pug_grpc_messages_sent_total
+		s.sent.Inc()
+	}
+	return err
+}
+
+func (s *monitoredServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		// pug_grpc_messages_received_total
+		s.received.Inc()
+	}
+	return err
+}
+
+func handleMetrics(metrics *promlib.Metrics, started time.Time, method, status string) {
 	// pug_requests_total
-	promlib.RequestsTotal.WithLabelValues(
+	metrics.RequestsTotal.WithLabelValues(
 		method,
 		"grpc",
 		status,
 	).Inc()
 
 	// pug_response_time_seconds
-	promlib.ResponseTime.WithLabelValues(
+	metrics.ResponseTime.WithLabelValues(
 		method,
 		"grpc",
 		status,
 	).Observe(promlib.CalculateObservation(started))
 }
-
-func isFromGrpcGateway(ctx context.Context) bool {
-	md, ok := metadata.FromIncomingContext(ctx)
-	if ok {
-		_, isGateway := md["x-from-grpc-gateway"]
-		if isGateway {
-			return true
-		}
-	}
-	return false
-}
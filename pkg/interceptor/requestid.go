@@ -0,0 +1,93 @@
+package interceptor
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/pug-go/pug-template/pkg/requestid"
+)
+
+// UnaryServerRequestId accepts the x-request-id metadata of the caller or
+// generates a new id, and puts it with x-source into the context, see
+// requestid.FromContext. The id is sent back in the response header.
+func UnaryServerRequestId() func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = withRequestId(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestid.MetadataKey, requestid.FromContext(ctx)))
+
+		return handler(ctx, req)
+	}
+}
+
+func StreamServerRequestId() func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := withRequestId(ss.Context())
+		_ = ss.SetHeader(metadata.Pairs(requestid.MetadataKey, requestid.FromContext(ctx)))
+
+		return handler(srv, &contextServerStream{
+			ServerStream: ss,
+			ctx:          ctx,
+		})
+	}
+}
+
+// UnaryClientRequestId passes the request id and the source of the context
+// to outbound calls:
+//
+//	grpc.NewClient(target, grpc.WithChainUnaryInterceptor(interceptor.UnaryClientRequestId()))
+func UnaryClientRequestId() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingRequestId(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+func StreamClientRequestId() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingRequestId(ctx), desc, cc, method, opts...)
+	}
+}
+
+func withRequestId(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	ctx = requestid.WithRequestId(ctx, requestid.Accept(lastValue(md, requestid.MetadataKey)))
+	if source := requestid.AcceptSource(lastValue(md, requestid.SourceMetadata)); source != "" {
+		ctx = requestid.WithSource(ctx, source)
+	}
+	return ctx
+}
+
+func outgoingRequestId(ctx context.Context) context.Context {
+	var pairs []string
+	if id := requestid.FromContext(ctx); id != "" {
+		pairs = append(pairs, requestid.MetadataKey, id)
+	}
+	if source := requestid.SourceFromContext(ctx); source != "" {
+		pairs = append(pairs, requestid.SourceMetadata, source)
+	}
+	if len(pairs) == 0 {
+		return ctx
+	}
+
+	// values set explicitly for the outbound call win
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	for i := 0; i < len(pairs); i += 2 {
+		if len(md.Get(pairs[i])) == 0 {
+			md.Set(pairs[i], pairs[i+1])
+		}
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// lastValue returns the last value of the key, the gateway appends its
+// values after the ones of the client.
+func lastValue(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[len(values)-1]
+}
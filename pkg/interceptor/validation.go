@@ -0,0 +1,221 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"buf.build/go/protovalidate"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	errorsv1pb "github.com/pug-go/pug-template/gen/pug/errors/v1"
+	"github.com/pug-go/pug-template/pkg/i18n"
+	"github.com/pug-go/pug-template/pkg/promlib"
+	"github.com/pug-go/pug-template/pkg/ref"
+	"github.com/pug-go/pug-template/pkg/violations"
+)
+
+// ErrorInfo is the data of violation message templates, see i18n.Catalog.
+type ErrorInfo struct {
+	// FieldName is the top level field, FieldPath is the full one, e.g.
+	// address and address.lines[2].
+	FieldName  string
+	FieldPath  string
+	RuleValue  any
+	FieldValue any
+}
+
+// UnaryServerValidations validates requests with protovalidate and renders
+// violation messages from bundle in the locale of the caller, see
+// UnaryServerLocale. Violations and validation latency are recorded to
+// metrics. Nil bundle and metrics mean i18n.Default and promlib.Default.
+func UnaryServerValidations(validator protovalidate.Validator, bundle *i18n.Bundle, metrics *promlib.Metrics) func(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	return newValidation(validator, bundle, metrics, nil).unary
+}
+
+func StreamServerValidations(validator protovalidate.Validator, bundle *i18n.Bundle, metrics *promlib.Metrics) func(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	return newValidation(validator, bundle, metrics, nil).stream
+}
+
+// localeFn picks the locale of the messages, nil means the caller locale.
+type localeFn func(ctx context.Context, bundle *i18n.Bundle) string
+
+type validation struct {
+	validator protovalidate.Validator
+	bundle    *i18n.Bundle
+	metrics   *promlib.Metrics
+	locale    localeFn
+}
+
+func newValidation(validator protovalidate.Validator, bundle *i18n.Bundle, metrics *promlib.Metrics, locale localeFn) *validation {
+	if bundle == nil {
+		bundle = i18n.Default
+	}
+	if metrics == nil {
+		metrics = promlib.Default
+	}
+	if locale == nil {
+		locale = callerLocale
+	}
+
+	return &validation{
+		validator: validator,
+		bundle:    bundle,
+		metrics:   metrics,
+		locale:    locale,
+	}
+}
+
+func (v *validation) unary(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if err := v.validateMsg(req, info.FullMethod, v.locale(ctx, v.bundle)); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (v *validation) stream(
+	srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	return handler(srv, &wrappedServerStream{
+		ServerStream: stream,
+		validation:   v,
+		method:       info.FullMethod,
+		locale:       v.locale(stream.Context(), v.bundle),
+	})
+}
+
+type wrappedServerStream struct {
+	grpc.ServerStream
+	validation *validation
+	method     string
+	locale     string
+}
+
+func (w *wrappedServerStream) RecvMsg(m interface{}) error {
+	if err := w.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return w.validation.validateMsg(m, w.method, w.locale)
+}
+
+func (v *validation) validateMsg(m interface{}, fullMethod, locale string) error {
+	var ok bool
+	var msg proto.Message
+
+	msg, ok = m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "unsupported message type: %T", m)
+	}
+
+	method := promlib.GetGrpcHandlerName(fullMethod)
+	started := time.Now()
+	err := v.validator.Validate(msg)
+	// pug_validation_duration_seconds
+	v.metrics.ValidationDuration.WithLabelValues(method).Observe(promlib.CalculateObservation(started))
+	if err == nil {
+		return nil
+	}
+
+	var valErr *protovalidate.ValidationError
+	if errors.As(err, &valErr) {
+		details := &errorsv1pb.Violations{}
+		for _, violation := range valErr.Violations {
+			data := ErrorInfo{
+				FieldName:  fieldName(violation),
+				FieldPath:  violations.FieldPath(violation.Proto.GetField()),
+				RuleValue:  violation.RuleValue.Interface(),
+				FieldValue: violation.FieldValue.Interface(),
+			}
+			// message declared in proto takes precedence over the catalog
+			text, ok := renderCustomMessage(msg.ProtoReflect().Descriptor(), violation, locale, data)
+			if !ok {
+				text, ok = v.bundle.Render(locale, violation.Proto.GetRuleId(), data)
+			}
+			if ok {
+				// overwrite the violation message with our localized/rendered text
+				violation.Proto.Message = ref.ToPtr(text)
+			}
+
+			details.Violations = append(details.Violations, &errorsv1pb.Violation{
+				Field:     data.FieldPath,
+				RuleId:    violation.Proto.GetRuleId(),
+				RuleValue: violations.RuleValue(violation.RuleValue),
+				Message:   violation.Proto.GetMessage(),
+				ForKey:    violation.Proto.GetForKey(),
+			})
+
+			// pug_validation_violations_total
+			v.metrics.ValidationViolationsTotal.WithLabelValues(
+				method,
+				violations.SchemaPath(violation.Proto.GetField()),
+				violation.Proto.GetRuleId(),
+			).Inc()
+		}
+		logViolations(fullMethod, valErr)
+
+		st := status.New(codes.InvalidArgument, err.Error())
+		ds, detErr := st.WithDetails(details, valErr.ToProto())
+		if detErr != nil {
+			return st.Err()
+		}
+		return ds.Err()
+	}
+
+	// CEL expression doesn't compile or type-check.
+	return status.Error(codes.Internal, err.Error())
+}
+
+// fieldName returns the top level field of the violation, empty for rules
+// of the request message itself.
+func fieldName(violation *protovalidate.Violation) string {
+	elements := violation.Proto.GetField().GetElements()
+	if len(elements) == 0 {
+		return ""
+	}
+	return elements[0].GetFieldName()
+}
+
+// logViolations logs the failed rules at debug level, values of fields
+// marked with debug_redact are hidden, others are truncated.
+func logViolations(fullMethod string, valErr *protovalidate.ValidationError) {
+	if !log.IsLevelEnabled(log.DebugLevel) {
+		return
+	}
+
+	rules := make([]string, 0, len(valErr.Violations))
+	for _, violation := range valErr.Violations {
+		rules = append(rules, fmt.Sprintf("%s: %s = %s",
+			violations.FieldPath(violation.Proto.GetField()),
+			violation.Proto.GetRuleId(),
+			violations.Redact(violation.FieldDescriptor, violation.FieldValue),
+		))
+	}
+
+	log.WithFields(log.Fields{
+		"method":     fullMethod,
+		"violations": rules,
+	}).Debug("validation failed")
+}
@@ -0,0 +1,118 @@
+package interceptor
+
+import (
+	"bytes"
+	"sync"
+	"text/template"
+
+	"buf.build/go/protovalidate"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	optionsv1pb "github.com/pug-go/pug-template/gen/pug/options/v1"
+	"github.com/pug-go/pug-template/pkg/i18n"
+)
+
+// customMessage is a violation message declared with pug proto options.
+type customMessage struct {
+	ruleID string
+	locale string
+	tmpl   *template.Template
+}
+
+// customMessages caches parsed messages of field and message descriptors.
+var customMessages sync.Map // protoreflect.Descriptor -> []customMessage
+
+// renderCustomMessage renders the message declared in proto for the
+// violation: the field option first, then the option of the message
+// containing the field, or of the violated message for message rules.
+func renderCustomMessage(
+	root protoreflect.MessageDescriptor,
+	violation *protovalidate.Violation,
+	locale string,
+	data ErrorInfo,
+) (string, bool) {
+	var descs []protoreflect.Descriptor
+	if fd := violation.FieldDescriptor; fd != nil {
+		descs = append(descs, fd, fd.ContainingMessage())
+	} else if md := violatedMessage(root, violation); md != nil {
+		descs = append(descs, md)
+	}
+
+	ruleID := violation.Proto.GetRuleId()
+	// no default locale: the catalog text in the caller locale is better
+	// than a custom one in another locale
+	fallbacks := append(i18n.Fallbacks(locale), "")
+	for _, desc := range descs {
+		messages := descriptorMessages(desc)
+		for _, l := range fallbacks {
+			for _, m := range messages {
+				if m.ruleID != ruleID || m.locale != l {
+					continue
+				}
+
+				var buf bytes.Buffer
+				if err := m.tmpl.Execute(&buf, data); err != nil {
+					log.Errorf("validation: %s: %s: %s", desc.FullName(), ruleID, err)
+					continue
+				}
+				return buf.String(), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// violatedMessage walks the field path of a message rule violation.
+func violatedMessage(root protoreflect.MessageDescriptor, violation *protovalidate.Violation) protoreflect.MessageDescriptor {
+	md := root
+	for _, el := range violation.Proto.GetField().GetElements() {
+		fd := md.Fields().ByNumber(protoreflect.FieldNumber(el.GetFieldNumber()))
+		if fd == nil {
+			return nil
+		}
+		if fd.IsMap() {
+			fd = fd.MapValue()
+		}
+		if md = fd.Message(); md == nil {
+			return nil
+		}
+	}
+
+	return md
+}
+
+func descriptorMessages(desc protoreflect.Descriptor) []customMessage {
+	if cached, ok := customMessages.Load(desc); ok {
+		return cached.([]customMessage)
+	}
+
+	var declared []*optionsv1pb.ValidationMessage
+	switch d := desc.(type) {
+	case protoreflect.FieldDescriptor:
+		opts, _ := proto.GetExtension(d.Options(), optionsv1pb.E_Field).(*optionsv1pb.Field)
+		declared = opts.GetMessages()
+	case protoreflect.MessageDescriptor:
+		opts, _ := proto.GetExtension(d.Options(), optionsv1pb.E_Message).(*optionsv1pb.Message)
+		declared = opts.GetMessages()
+	}
+
+	messages := make([]customMessage, 0, len(declared))
+	for _, m := range declared {
+		t, err := template.New(m.GetRuleId()).Parse(m.GetText())
+		if err != nil {
+			log.Errorf("validation: invalid message of %s: %s: %s", desc.FullName(), m.GetRuleId(), err)
+			continue
+		}
+		messages = append(messages, customMessage{
+			ruleID: m.GetRuleId(),
+			locale: i18n.Normalize(m.GetLocale()),
+			tmpl:   t,
+		})
+	}
+	customMessages.Store(desc, messages)
+
+	return messages
+}
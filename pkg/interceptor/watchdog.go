@@ -0,0 +1,22 @@
+package interceptor
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/pug-go/pug-template/pkg/healthcheck"
+)
+
+// UnaryServerWatchdog reports unary calls to activity, so that the liveness
+// check fails when handlers are stuck, see healthcheck.Watchdog.WatchActivity. There
+// is no stream interceptor: streams may last for any time. Nil activity
+// is not watched.
+func UnaryServerWatchdog(activity *healthcheck.Activity) func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		done := activity.Start()
+		defer done()
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,137 @@
+package listener
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	Scheme_Tcp  = "tcp"
+	Scheme_Unix = "unix"
+	Scheme_Fd   = "fd"
+)
+
+// Listen creates a listener from spec:
+//   - tcp://host:port, e.g. tcp://127.0.0.1:8080 or tcp://:0 for a random port
+//   - unix:///path/to.sock, a stale socket file is removed
+//   - fd://N, a listening socket inherited as file descriptor N, e.g. from
+//     systemd socket activation
+//
+// A spec without scheme, e.g. ":8080", is a tcp address.
+func Listen(spec string) (net.Listener, error) {
+	scheme, address, err := Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	switch scheme {
+	case Scheme_Tcp:
+		return net.Listen("tcp", address)
+	case Scheme_Unix:
+		if err = removeStaleSocket(address); err != nil {
+			return nil, err
+		}
+		return net.Listen("unix", address)
+	case Scheme_Fd:
+		fd, err := strconv.Atoi(address)
+		if err != nil || fd < 0 {
+			return nil, fmt.Errorf("listener: invalid file descriptor in %q", spec)
+		}
+		return FileListener(uintptr(fd), spec)
+	}
+
+	return nil, fmt.Errorf("listener: unsupported scheme %q in %q", scheme, spec)
+}
+
+// Parse splits spec into scheme and address.
+func Parse(spec string) (scheme, address string, err error) {
+	if !strings.Contains(spec, "://") {
+		return Scheme_Tcp, spec, nil
+	}
+
+	u, err := url.Parse(spec)
+	if err != nil {
+		return "", "", fmt.Errorf("listener: invalid spec %q: %w", spec, err)
+	}
+
+	switch u.Scheme {
+	case Scheme_Tcp, Scheme_Fd:
+		return u.Scheme, u.Host, nil
+	case Scheme_Unix:
+		// both unix:///abs/path and unix://relative/path
+		return u.Scheme, u.Host + u.Path, nil
+	}
+
+	return "", "", fmt.Errorf("listener: unsupported scheme %q in %q", u.Scheme, spec)
+}
+
+// FileListener creates a listener from an inherited file descriptor.
+func FileListener(fd uintptr, name string) (net.Listener, error) {
+	f := os.NewFile(fd, name)
+	if f == nil {
+		return nil, fmt.Errorf("listener: invalid file descriptor %d", fd)
+	}
+	defer f.Close()
+
+	l, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("listener: %s is not a listening socket: %w", name, err)
+	}
+	return l, nil
+}
+
+// File returns a duplicate of the listener file descriptor, the listener
+// must be a tcp or unix one.
+func File(l net.Listener) (*os.File, error) {
+	fl, ok := l.(interface{ File() (*os.File, error) })
+	if !ok {
+		return nil, fmt.Errorf("listener: %T has no file descriptor", l)
+	}
+	return fl.File()
+}
+
+// Target returns the address to dial a listener bound on addr from the same
+// host, in grpc target format.
+func Target(addr net.Addr) string {
+	if addr.Network() == "unix" {
+		return "unix://" + addr.String()
+	}
+
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// Port returns the port of a tcp addr, 0 for other networks.
+func Port(addr net.Addr) int {
+	if tcpAddr, ok := addr.(*net.TCPAddr); ok {
+		return tcpAddr.Port
+	}
+	return 0
+}
+
+func removeStaleSocket(path string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&fs.ModeSocket == 0 {
+		return fmt.Errorf("listener: %s exists and is not a socket", path)
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,92 @@
+package listener
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+)
+
+// Network_Pipe is the network of Pipe connection addresses, they can't be
+// made by remote peers, so it identifies in-process callers.
+//
+//goland:noinspection GoSnakeCaseUsage
+const Network_Pipe = "pug-pipe"
+
+var errPipeClosed = errors.New("listener: pipe is closed")
+
+// PipeListener is an in-memory listener, connections are made by
+// DialContext in the same process.
+type PipeListener struct {
+	conns     chan net.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func Pipe() *PipeListener {
+	return &PipeListener{
+		conns: make(chan net.Conn),
+		done:  make(chan struct{}),
+	}
+}
+
+func (l *PipeListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, errPipeClosed
+	}
+}
+
+func (l *PipeListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.done)
+	})
+	return nil
+}
+
+func (l *PipeListener) Addr() net.Addr {
+	return pipeAddr{}
+}
+
+// DialContext connects to the listener, it blocks until the connection is
+// accepted.
+func (l *PipeListener) DialContext(ctx context.Context) (net.Conn, error) {
+	server, client := net.Pipe()
+	select {
+	case l.conns <- &pipeConn{Conn: server}:
+		return &pipeConn{Conn: client}, nil
+	case <-l.done:
+		_ = server.Close()
+		_ = client.Close()
+		return nil, errPipeClosed
+	case <-ctx.Done():
+		_ = server.Close()
+		_ = client.Close()
+		return nil, ctx.Err()
+	}
+}
+
+// pipeConn reports Network_Pipe addresses instead of the net.Pipe ones.
+type pipeConn struct {
+	net.Conn
+}
+
+func (c *pipeConn) LocalAddr() net.Addr {
+	return pipeAddr{}
+}
+
+func (c *pipeConn) RemoteAddr() net.Addr {
+	return pipeAddr{}
+}
+
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string {
+	return Network_Pipe
+}
+
+func (pipeAddr) String() string {
+	return Network_Pipe
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pug-go/pug-template/pkg/accesslog"
+	"github.com/pug-go/pug-template/pkg/requestid"
+)
+
+// NewAccessLog returns a middleware writing a record of the request to
+// logger, nil logger disables it. Payloads are logged by the grpc
+// interceptor, see interceptor.UnaryServerAccessLog.
+func NewAccessLog(logger *accesslog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !logger.Enabled() {
+			return next
+		}
+		return accessLogHandler(logger, next)
+	}
+}
+
+func accessLogHandler(logger *accesslog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+
+		var body *countingBody
+		if r.Body != nil {
+			body = &countingBody{ReadCloser: r.Body}
+			r.Body = body
+		}
+		aw := &accessLogWriter{ResponseWriter: w}
+		next.ServeHTTP(aw, r)
+
+		code := aw.status
+		if code == 0 {
+			code = http.StatusOK
+		}
+		if !logger.Sampled(code >= http.StatusBadRequest) {
+			return
+		}
+
+		fields := log.Fields{
+			"protocol":      "http",
+			"method":        r.Method,
+			"path":          r.URL.Path,
+			"peer":          r.RemoteAddr,
+			"code":          code,
+			"duration_ms":   float64(time.Since(started).Microseconds()) / 1000,
+			"response_size": aw.size,
+		}
+		if aw.pattern != "" {
+			fields["route"] = aw.pattern
+		}
+		if body != nil {
+			fields["request_size"] = body.size
+		}
+		if id := requestid.FromContext(r.Context()); id != "" {
+			fields["request_id"] = id
+		}
+		if source := requestid.SourceFromContext(r.Context()); source != "" {
+			fields["source"] = source
+		}
+		logger.Log(fields)
+	})
+}
+
+// accessLogWriter records the status, size and the gateway route pattern of
+// the response, the pattern header is removed by the prometheus middleware
+// after the response is written.
+type accessLogWriter struct {
+	http.ResponseWriter
+	status  int
+	size    int
+	pattern string
+}
+
+func (w *accessLogWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+		w.pattern = w.Header().Get("pattern")
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *accessLogWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+		w.pattern = w.Header().Get("pattern")
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
+
+type countingBody struct {
+	io.ReadCloser
+	size int
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += n
+	return n, err
+}
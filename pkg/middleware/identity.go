@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/pug-go/pug-template/pkg/tlsconf"
+)
+
+// NewIdentity puts the caller identity from the client certificate verified
+// by tlsReloader into the request context, see tlsconf.IdentityFromContext.
+// The gateway forwards it to grpc. Nil tlsReloader puts none.
+func NewIdentity(tlsReloader *tlsconf.Reloader) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if id := tlsReloader.PeerIdentity(r.TLS); id != nil {
+				r = r.WithContext(tlsconf.WithIdentity(r.Context(), id))
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -1,10 +1,21 @@
 package middleware
 
-import "net/http"
+import (
+	"net/http"
 
-var Default = []func(http.Handler) http.Handler{
-	Prometheus,
-	Recovery,
+	"github.com/pug-go/pug-template/pkg/promlib"
+)
+
+// Default middlewares record metrics to promlib.Default.
+var Default = NewDefault(nil)
+
+// NewDefault returns the default middlewares recording metrics to metrics,
+// nil means promlib.Default, see NewPrometheus.
+func NewDefault(metrics *promlib.Metrics) []func(http.Handler) http.Handler {
+	return []func(http.Handler) http.Handler{
+		NewPrometheus(metrics),
+		Recovery,
+	}
 }
 
 func New(middlewares ...func(http.Handler) http.Handler) []func(http.Handler) http.Handler {
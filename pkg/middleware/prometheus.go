@@ -7,26 +7,44 @@ import (
 	"github.com/pug-go/pug-template/pkg/promlib"
 )
 
+// Prometheus records metrics of the request to promlib.Default.
 func Prometheus(next http.Handler) http.Handler {
+	return NewPrometheus(nil)(next)
+}
+
+// NewPrometheus returns a middleware recording metrics of the request to
+// metrics, nil means promlib.Default at the time of the request.
+func NewPrometheus(metrics *promlib.Metrics) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return prometheusHandler(metrics, next)
+	}
+}
+
+func prometheusHandler(metrics *promlib.Metrics, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		started := time.Now()
 
 		rw := &rwWrapper{ResponseWriter: w}
 		next.ServeHTTP(rw, r)
 
+		metrics := metrics
+		if metrics == nil {
+			metrics = promlib.Default
+		}
+
 		status := promlib.HttpCodeToStatus(rw.status)
 		pattern := popParam("pattern", w.Header())
 		handler := "HTTP " + r.Method + ": " + pattern
 
 		// pug_requests_total
-		promlib.RequestsTotal.WithLabelValues(
+		metrics.RequestsTotal.WithLabelValues(
 			handler,
 			"http",
 			status,
 		).Inc()
 
 		// pug_response_time_seconds
-		promlib.ResponseTime.WithLabelValues(
+		metrics.ResponseTime.WithLabelValues(
 			handler,
 			"http",
 			status,
@@ -1,10 +1,13 @@
 package middleware
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 
 	log "github.com/sirupsen/logrus"
+
+	"github.com/pug-go/pug-template/pkg/requestid"
 )
 
 func Recovery(next http.Handler) http.Handler {
@@ -17,7 +20,11 @@ func Recovery(next http.Handler) http.Handler {
 
 				w.Header().Set("Content-Type", "application/json; charset=utf-8")
 				w.WriteHeader(http.StatusInternalServerError)
-				_, err = w.Write([]byte(`{"code": 13, "message": "Internal"}`))
+				body := map[string]any{"code": 13, "message": "Internal"}
+				if id := requestid.FromContext(r.Context()); id != "" {
+					body["request_id"] = id
+				}
+				err = json.NewEncoder(w).Encode(body)
 				if err != nil {
 					log.Error(err)
 				}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/pug-go/pug-template/pkg/requestid"
+)
+
+// RequestId accepts the X-Request-Id header of the caller or generates a
+// new id, and puts it with X-Source into the request context, see
+// requestid.FromContext. The id is echoed in the response header.
+func RequestId(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := requestid.Accept(r.Header.Get(requestid.Header))
+		ctx := requestid.WithRequestId(r.Context(), id)
+		if source := requestid.AcceptSource(r.Header.Get(requestid.SourceHeader)); source != "" {
+			ctx = requestid.WithSource(ctx, source)
+		}
+
+		w.Header().Set(requestid.Header, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
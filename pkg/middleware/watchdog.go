@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/pug-go/pug-template/pkg/healthcheck"
+)
+
+// NewWatchdog reports requests to activity, so that the liveness check
+// fails when handlers are stuck, see healthcheck.Watchdog.WatchActivity. Nil
+// activity is not watched.
+func NewWatchdog(activity *healthcheck.Activity) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			done := activity.Start()
+			defer done()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -19,19 +21,137 @@ const (
 	Status_OK            = "ok"
 )
 
+// grpc call types
+//
+//goland:noinspection GoSnakeCaseUsage
+const (
+	GrpcType_Unary        = "unary"
+	GrpcType_ClientStream = "client_stream"
+	GrpcType_ServerStream = "server_stream"
+	GrpcType_BidiStream   = "bidi_stream"
+)
+
+// Metrics are the pug metrics registered in one registry, every App has
+// its own, see NewMetrics.
+type Metrics struct {
+	ResponseTime              *prometheus.HistogramVec
+	RequestsTotal             *prometheus.CounterVec
+	HealthCheckStatus         *prometheus.GaugeVec
+	HealthCheckDuration       *prometheus.GaugeVec
+	HealthCheckFailuresTotal  *prometheus.CounterVec
+	ValidationViolationsTotal *prometheus.CounterVec
+	ValidationDuration        *prometheus.HistogramVec
+	GrpcMessagesReceivedTotal *prometheus.CounterVec
+	GrpcMessagesSentTotal     *prometheus.CounterVec
+	GrpcStreamDuration        *prometheus.HistogramVec
+	GrpcStreamsActive         *prometheus.GaugeVec
+}
+
+// Default metrics are used by code which isn't given the App metrics. They
+// are registered in prometheus.DefaultRegisterer unless main replaces them
+// with the App ones, see SetDefault.
+var Default = NewMetrics(prometheus.DefaultRegisterer)
+
+// Deprecated: use Metrics of the App or Default.
 var (
-	ResponseTime = promauto.NewHistogramVec(prometheus.HistogramOpts{
-		Namespace: "pug",
-		Name:      "response_time_seconds",
-		Help:      "Histogram of application RT for any kind of requests: HTTP, gRPC (seconds).",
-	}, []string{"handler", "protocol", "status"})
-	RequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
-		Namespace: "pug",
-		Name:      "requests_total",
-		Help:      "Counter of application requests for any kind of requests: HTTP, gRPC.",
-	}, []string{"handler", "protocol", "status"})
+	ResponseTime             = Default.ResponseTime
+	RequestsTotal            = Default.RequestsTotal
+	HealthCheckStatus        = Default.HealthCheckStatus
+	HealthCheckDuration      = Default.HealthCheckDuration
+	HealthCheckFailuresTotal = Default.HealthCheckFailuresTotal
 )
 
+// SetDefault makes m the Default metrics and the deprecated globals, so
+// that code using them records to the registry served by an App, e.g.
+// promlib.SetDefault(app.Metrics()) in main. It's not synchronized, so it
+// must be called before any server is started.
+func SetDefault(m *Metrics) {
+	Default = m
+	ResponseTime = m.ResponseTime
+	RequestsTotal = m.RequestsTotal
+	HealthCheckStatus = m.HealthCheckStatus
+	HealthCheckDuration = m.HealthCheckDuration
+	HealthCheckFailuresTotal = m.HealthCheckFailuresTotal
+}
+
+// NewRegistry creates a registry with go runtime and process collectors,
+// like the default one.
+func NewRegistry() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return reg
+}
+
+// NewMetrics creates the metrics and registers them in reg, it panics if
+// they are registered in reg already.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	factory := promauto.With(reg)
+
+	return &Metrics{
+		ResponseTime: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "pug",
+			Name:      "response_time_seconds",
+			Help:      "Histogram of application RT for any kind of requests: HTTP, gRPC (seconds).",
+		}, []string{"handler", "protocol", "status"}),
+		RequestsTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "pug",
+			Name:      "requests_total",
+			Help:      "Counter of application requests for any kind of requests: HTTP, gRPC.",
+		}, []string{"handler", "protocol", "status"}),
+		HealthCheckStatus: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "pug",
+			Name:      "health_check_status",
+			Help:      "Result of the last health check run: 1 passed, 0 failed.",
+		}, []string{"check", "kind", "level"}),
+		HealthCheckDuration: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "pug",
+			Name:      "health_check_duration_seconds",
+			Help:      "Duration of the last health check run (seconds).",
+		}, []string{"check", "kind", "level"}),
+		HealthCheckFailuresTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "pug",
+			Name:      "health_check_failures_total",
+			Help:      "Counter of failed health check runs.",
+		}, []string{"check", "kind", "level"}),
+		ValidationViolationsTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "pug",
+			Name:      "validation_violations_total",
+			Help:      "Counter of request validation violations by field path without subscripts.",
+		}, []string{"method", "field", "rule"}),
+		ValidationDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "pug",
+			Name:      "validation_duration_seconds",
+			Help:      "Histogram of request validation time, including CEL rules (seconds).",
+			Buckets:   []float64{.00001, .00005, .0001, .0005, .001, .005, .01, .05, .1, .5},
+		}, []string{"method"}),
+		GrpcMessagesReceivedTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "pug",
+			Name:      "grpc_messages_received_total",
+			Help:      "Counter of gRPC messages received by the server, including requests of unary calls.",
+		}, []string{"method", "type"}),
+		GrpcMessagesSentTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "pug",
+			Name:      "grpc_messages_sent_total",
+			Help:      "Counter of gRPC messages sent by the server, including responses of unary calls.",
+		}, []string{"method", "type"}),
+		GrpcStreamDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "pug",
+			Name:      "grpc_stream_duration_seconds",
+			Help:      "Histogram of gRPC stream lifetime (seconds).",
+			Buckets:   []float64{.1, .5, 1, 5, 10, 30, 60, 300, 900, 1800, 3600},
+		}, []string{"method", "type", "status"}),
+		GrpcStreamsActive: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "pug",
+			Name:      "grpc_streams_active",
+			Help:      "Number of gRPC streams being served.",
+		}, []string{"method", "type"}),
+	}
+}
+
 func HttpCodeToStatus(code int) string {
 	switch {
 	case code >= 500:
@@ -98,6 +218,19 @@ func GrpcErrorToStatus(err error) string {
 	return Status_Unknown
 }
 
+// GetGrpcType returns the call type of the stream info.
+func GetGrpcType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return GrpcType_BidiStream
+	case info.IsClientStream:
+		return GrpcType_ClientStream
+	case info.IsServerStream:
+		return GrpcType_ServerStream
+	}
+	return GrpcType_Unary
+}
+
 func GetGrpcHandlerName(fullMethod string) string {
 	fmSl := strings.Split(fullMethod, "/")
 	return fmSl[len(fmSl)-1]
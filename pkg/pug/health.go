@@ -0,0 +1,53 @@
+package pug
+
+import (
+	"github.com/pug-go/pug-template/pkg/healthcheck"
+)
+
+// HealthCheck returns the handler behind /live, /ready, /startup and grpc
+// health.
+func (a *App) HealthCheck() healthcheck.Handler {
+	return a.hc
+}
+
+// Watchdog returns the watchdog behind the "watchdog" liveness check,
+// critical loops of the app should be watched by it.
+func (a *App) Watchdog() *healthcheck.Watchdog {
+	return a.watchdog
+}
+
+// AddLivenessCheck adds a check failing when the app must be restarted,
+// see healthcheck.Handler.
+func (a *App) AddLivenessCheck(name string, check healthcheck.Check, opts ...healthcheck.CheckOption) {
+	a.hc.AddLivenessCheck(name, check, opts...)
+}
+
+// AddReadinessCheck adds a check failing when the app can't serve requests
+// because of a dependency, see healthcheck.Handler.
+func (a *App) AddReadinessCheck(name string, check healthcheck.Check, opts ...healthcheck.CheckOption) {
+	a.hc.AddReadinessCheck(name, check, opts...)
+}
+
+// AddStartupCheck adds a check that must pass once before the app becomes
+// ready, see healthcheck.Handler.
+func (a *App) AddStartupCheck(name string, check healthcheck.Check, opts ...healthcheck.CheckOption) {
+	a.hc.AddStartupCheck(name, check, opts...)
+}
+
+// AddResource registers a dependency of the app, e.g. a database pool or an
+// upstream client. closeFn is called on shutdown after the servers are
+// stopped, resources are closed in reverse order of adding. check is added
+// as readiness check if not nil.
+func (a *App) AddResource(
+	name string,
+	closeFn func() error,
+	check healthcheck.Check,
+	opts ...healthcheck.CheckOption,
+) {
+	if closeFn != nil {
+		a.closer.AddFunc(name, closeFn)
+	}
+	if check != nil {
+		a.hc.AddReadinessCheck(name, check, opts...)
+	}
+}
@@ -0,0 +1,73 @@
+package pug
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Phase is a stage of the App lifecycle.
+type Phase int32
+
+const (
+	// PhaseStarting is set while warm-up tasks run, until the public
+	// servers are launched.
+	PhaseStarting Phase = iota
+	// PhaseReady means the app accepts traffic.
+	PhaseReady
+	// PhaseDraining means shutdown has started: readiness fails and
+	// in-flight requests are being finished.
+	PhaseDraining
+	// PhaseStopped means all servers and resources are closed.
+	PhaseStopped
+)
+
+var errNotReady = errors.New("app is not ready")
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseStarting:
+		return "starting"
+	case PhaseReady:
+		return "ready"
+	case PhaseDraining:
+		return "draining"
+	case PhaseStopped:
+		return "stopped"
+	}
+	return "unknown"
+}
+
+// Phase returns the current lifecycle phase.
+func (a *App) Phase() Phase {
+	return Phase(a.phase.Load())
+}
+
+// setPhase moves the phase forward, it's never moved backwards, e.g. to
+// ready by warm-up finishing after shutdown has started.
+func (a *App) setPhase(p Phase) {
+	var prev Phase
+	for {
+		prev = a.Phase()
+		if prev >= p {
+			return
+		}
+		if a.phase.CompareAndSwap(int32(prev), int32(p)) {
+			break
+		}
+	}
+	log.WithFields(log.Fields{
+		"from": prev.String(),
+		"to":   p.String(),
+	}).Info("lifecycle phase changed")
+}
+
+// checkPhase is a readiness check that fails as soon as shutdown begins.
+func (a *App) checkPhase(_ context.Context) error {
+	if a.Phase() != PhaseReady {
+		return fmt.Errorf("%w: %s", errNotReady, a.Phase())
+	}
+	return nil
+}
@@ -4,140 +4,347 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
+	"sync/atomic"
 	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/cors"
 	log "github.com/sirupsen/logrus"
 	swagger "github.com/swaggo/http-swagger"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 
 	"github.com/pug-go/pug-template/pkg/closer"
 	"github.com/pug-go/pug-template/pkg/healthcheck"
+	"github.com/pug-go/pug-template/pkg/inflight"
+	"github.com/pug-go/pug-template/pkg/listener"
 	"github.com/pug-go/pug-template/pkg/middleware"
+	"github.com/pug-go/pug-template/pkg/promlib"
+	"github.com/pug-go/pug-template/pkg/requestid"
+	"github.com/pug-go/pug-template/pkg/tlsconf"
 )
 
 const gracefulTimeout = 10 * time.Second
 const gracefulDelay = 3 * time.Second
 
+// public servers close priorities: the gateway is stopped before grpc, it
+// serves its requests through grpc.
+const (
+	priorityGrpc = iota + 1
+	priorityHttp
+)
+
 type Handler interface {
 	RegisterGrpcServices(server *grpc.Server)
 	InitHttpRoutes(mux *runtime.ServeMux, conn *grpc.ClientConn) error
 }
 
 type GrpcServer interface {
-	Run(port int16) error
+	Serve(listener net.Listener) error
 	Stop(ctx context.Context) error
+	http.Handler
 }
 
 type HttpServer interface {
-	Run(grpcPort, httpPort int16) error
+	Serve(listener net.Listener, grpcTarget string) error
+	Handler(conn *grpc.ClientConn) (http.Handler, error)
 	Stop(ctx context.Context) error
 	Use(middleware func(next http.Handler) http.Handler)
 }
 
 type App struct {
+	closer       *closer.Closer
 	publicCloser *closer.Closer
 	debugCloser  *closer.Closer
 	hc           healthcheck.Handler
+	grpcHealth   *healthcheck.GrpcHealth
+	watchdog     *healthcheck.Watchdog
+	metrics      *promlib.Metrics
+	inflight     *inflight.Counter
+	phase        atomic.Int32
+	upgrading    atomic.Bool
+	stop         context.CancelFunc
+	listeners    *listeners
+	warmups      []warmupTask
 	config       Config
 }
 
+type listeners struct {
+	grpc  net.Listener
+	http  net.Listener
+	debug net.Listener
+}
+
+// Addrs are the addresses the app listens on.
+type Addrs struct {
+	// Grpc is nil in single port mode.
+	Grpc  net.Addr
+	Http  net.Addr
+	Debug net.Addr
+}
+
 type Config struct {
 	ServiceName string
 	Domain      string
-	GrpcPort    int16
-	HttpPort    int16
-	DebugPort   int16
+	// GrpcListen, HttpListen and DebugListen are listener specs, see
+	// listener.Listen: tcp://127.0.0.1:8080, unix:///run/pug.sock, fd://3.
+	GrpcListen  string
+	HttpListen  string
+	DebugListen string
+	// SinglePort serves grpc and http gateway together on HttpListen,
+	// GrpcListen is not used then.
+	SinglePort bool
+	// HttpTLS is used by the single port listener, DebugTLS by the debug
+	// server. Nil means plain text.
+	HttpTLS  *tlsconf.Reloader
+	DebugTLS *tlsconf.Reloader
+	// Registry serves /metrics, the app metrics are registered in it. Nil
+	// means a new registry, see promlib.NewRegistry.
+	Registry *prometheus.Registry
 }
 
 func NewApp(config Config) (*App, error) {
-	return &App{
+	if config.Registry == nil {
+		config.Registry = promlib.NewRegistry()
+	}
+	metrics := promlib.NewMetrics(config.Registry)
+
+	a := &App{
+		closer:       closer.NewCloser(),
 		publicCloser: closer.NewCloser(),
 		debugCloser:  closer.NewCloser(),
-		hc:           healthcheck.NewHandler(),
+		hc:           healthcheck.NewHandler(healthcheck.WithMetrics(metrics)),
+		inflight:     inflight.New(),
+		watchdog:     healthcheck.NewWatchdog(),
+		metrics:      metrics,
 		config:       config,
-	}, nil
+	}
+	a.hc.AddLivenessCheck("watchdog", a.watchdog.Check)
+	a.hc.AddReadinessCheck("lifecycle", a.checkPhase)
+	a.hc.AddStartupCheck("warmup", a.checkWarmup)
+	a.grpcHealth = healthcheck.NewGrpcHealth(a.hc)
+
+	return a, nil
+}
+
+// Config returns the app config.
+func (a *App) Config() Config {
+	return a.config
+}
+
+// Closer returns the closer of the app resources, it's closed on shutdown
+// after the servers are stopped.
+func (a *App) Closer() *closer.Closer {
+	return a.closer
+}
+
+// Registry returns the registry served by /metrics, register metrics of
+// the service in it.
+func (a *App) Registry() *prometheus.Registry {
+	return a.config.Registry
+}
+
+// Metrics returns the pug metrics registered in Registry, the servers must
+// record requests to them.
+func (a *App) Metrics() *promlib.Metrics {
+	return a.metrics
+}
+
+// GrpcHealth returns grpc.health.v1.Health service reporting the app checks,
+// the grpc server must register it.
+func (a *App) GrpcHealth() *healthcheck.GrpcHealth {
+	return a.grpcHealth
 }
 
+// Inflight returns the counter of requests being served. The grpc server
+// must count its calls with it, so that shutdown can drain them.
+func (a *App) Inflight() *inflight.Counter {
+	return a.inflight
+}
+
+// Listen binds the app listeners. Run calls it if it wasn't called before,
+// call it explicitly to know the bound addresses before Run, e.g. for
+// tcp://127.0.0.1:0 in tests.
+func (a *App) Listen() (Addrs, error) {
+	if a.listeners != nil {
+		return a.Addrs(), nil
+	}
+
+	inherited, err := inheritedListeners()
+	if err != nil {
+		return Addrs{}, err
+	}
+
+	ls := &listeners{}
+	specs := []struct {
+		name string
+		spec string
+		dst  *net.Listener
+	}{
+		{"grpc", a.config.GrpcListen, &ls.grpc},
+		{"http", a.config.HttpListen, &ls.http},
+		{"debug", a.config.DebugListen, &ls.debug},
+	}
+	for _, s := range specs {
+		if s.name == "grpc" && a.config.SinglePort {
+			continue
+		}
+		if l, ok := inherited[s.name]; ok {
+			*s.dst = l
+			delete(inherited, s.name)
+			continue
+		}
+
+		l, err := listener.Listen(s.spec)
+		if err != nil {
+			ls.close()
+			return Addrs{}, fmt.Errorf("%s: failed to listen on %q: %w", s.name, s.spec, err)
+		}
+		*s.dst = l
+	}
+	for _, l := range inherited {
+		_ = l.Close()
+	}
+	a.listeners = ls
+
+	return a.Addrs(), nil
+}
+
+// Addrs returns the bound addresses, they are empty before Listen.
+func (a *App) Addrs() Addrs {
+	var addrs Addrs
+	if a.listeners == nil {
+		return addrs
+	}
+	if a.listeners.grpc != nil {
+		addrs.Grpc = a.listeners.grpc.Addr()
+	}
+	addrs.Http = a.listeners.http.Addr()
+	addrs.Debug = a.listeners.debug.Addr()
+
+	return addrs
+}
+
+func (ls *listeners) close() {
+	for _, l := range []net.Listener{ls.grpc, ls.http, ls.debug} {
+		if l != nil {
+			_ = l.Close()
+		}
+	}
+}
+
+// Run starts the servers and blocks until ctx is done or any server fails.
+// The debug server starts first, the public servers start accepting traffic
+// after the warm-up tasks are done. In both cases the app is gracefully shut
+// down and all closers are run. The first server or warm-up error is
+// returned joined with shutdown errors, nil means a clean shutdown.
 func (a *App) Run(
+	ctx context.Context,
 	grpcServer GrpcServer,
 	httpServer HttpServer,
-) {
-	// starting servers
-	go a.startGrpcServer(grpcServer)
-	go a.startHttpServer(httpServer)
-	go a.startDebugServer()
+) error {
+	if _, err := a.Listen(); err != nil {
+		return err
+	}
 
-	// gracefully shutdown
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
-	<-quit
-	a.shutdown()
-}
-
-func (a *App) startGrpcServer(grpcServer GrpcServer) {
-	a.publicCloser.Add(func() error {
-		ctx, cancel := context.WithTimeout(context.Background(), gracefulTimeout)
-		defer cancel()
-
-		done := make(chan struct{})
-		go func() {
-			_ = grpcServer.Stop(ctx)
-			close(done)
-		}()
-		select {
-		case <-done:
-			log.Info("grpc: gracefully stopped")
-		case <-ctx.Done():
-			err := fmt.Errorf("error during shutdown server: %w", ctx.Err())
-			_ = grpcServer.Stop(ctx)
-			return fmt.Errorf("grpc: force stopped: %w", err)
+	ctx, a.stop = context.WithCancel(ctx)
+	defer a.stop()
+	g, gctx := errgroup.WithContext(ctx)
+
+	// starting servers, public ones after warm-up
+	var public []func() error
+	if a.config.SinglePort {
+		public = append(public, a.startSinglePortServer(grpcServer, httpServer))
+	} else {
+		public = append(public, a.startGrpcServer(grpcServer), a.startHttpServer(httpServer))
+	}
+	g.Go(a.startDebugServer())
+	g.Go(func() error {
+		if err := a.warmup(gctx); err != nil && gctx.Err() == nil {
+			return err
+		}
+		if gctx.Err() != nil {
+			// stopped during warm-up
+			return nil
+		}
+		for _, serve := range public {
+			g.Go(serve)
 		}
+		a.setPhase(PhaseReady)
+		notifyUpgradeReady()
+		return nil
+	})
 
+	// gracefully shutdown
+	var shutdownErr error
+	g.Go(func() error {
+		<-gctx.Done()
+		shutdownErr = a.shutdown()
 		return nil
 	})
 
-	if err := grpcServer.Run(a.config.GrpcPort); err != nil {
-		log.Fatalf("grpc: error occurred while running server: %s", err.Error())
-	}
+	err := g.Wait()
+	return errors.Join(err, shutdownErr)
 }
 
-func (a *App) startHttpServer(httpServer HttpServer) {
-	swaggerDomain := fmt.Sprintf("://%s:%d", a.config.Domain, a.config.DebugPort)
-	c := cors.New(cors.Options{
-		AllowedOrigins:   []string{"http" + swaggerDomain, "https" + swaggerDomain},
-		AllowedMethods:   []string{http.MethodHead, http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions},
-		AllowedHeaders:   []string{"*"},
-		AllowCredentials: true,
-	})
+func (a *App) startGrpcServer(grpcServer GrpcServer) func() error {
+	a.publicCloser.Add("grpc", func(ctx context.Context) error {
+		if err := grpcServer.Stop(ctx); err != nil {
+			return fmt.Errorf("force stopped: %w", err)
+		}
+		log.Info("grpc: gracefully stopped")
+
+		return nil
+	}, closer.WithPriority(priorityGrpc), closer.WithTimeout(gracefulTimeout))
 
-	a.publicCloser.Add(func() error {
-		ctx, cancel := context.WithTimeout(context.Background(), gracefulTimeout)
-		defer cancel()
+	return func() error {
+		if err := grpcServer.Serve(a.listeners.grpc); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			return fmt.Errorf("grpc: error occurred while running server: %w", err)
+		}
+		return nil
+	}
+}
 
+func (a *App) startHttpServer(httpServer HttpServer) func() error {
+	a.publicCloser.Add("http.public", func(ctx context.Context) error {
 		if err := httpServer.Stop(ctx); err != nil {
-			return fmt.Errorf("http.public: error during shutdown: %w", err)
+			return fmt.Errorf("error during shutdown: %w", err)
 		}
 		log.Info("http.public: gracefully stopped")
 
 		return nil
-	})
+	}, closer.WithPriority(priorityHttp), closer.WithTimeout(gracefulTimeout))
 
-	httpServer.Use(c.Handler)
+	a.useHttpMiddlewares(httpServer)
 
-	log.Info("debug server listening on: ", a.config.DebugPort)
-	if err := httpServer.Run(a.config.GrpcPort, a.config.HttpPort); err != nil && !errors.Is(err, http.ErrServerClosed) {
-		log.Fatalf("http.public: error occurred while running server: %s", err.Error())
+	return func() error {
+		grpcTarget := listener.Target(a.listeners.grpc.Addr())
+		if err := httpServer.Serve(a.listeners.http, grpcTarget); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("http.public: error occurred while running server: %w", err)
+		}
+		return nil
 	}
 }
 
-func (a *App) startDebugServer() {
+func (a *App) useHttpMiddlewares(httpServer HttpServer) {
+	swaggerDomain := "://" + a.host(a.listeners.debug.Addr())
+	c := cors.New(cors.Options{
+		AllowedOrigins:   []string{"http" + swaggerDomain, "https" + swaggerDomain},
+		AllowedMethods:   []string{http.MethodHead, http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions},
+		AllowedHeaders:   []string{"*"},
+		ExposedHeaders:   []string{requestid.Header},
+		AllowCredentials: true,
+	})
+
+	httpServer.Use(c.Handler)
+	httpServer.Use(a.inflight.Middleware)
+}
+
+func (a *App) startDebugServer() func() error {
 	mux := http.NewServeMux()
 
 	swaggerRoute := "docs"
@@ -182,8 +389,8 @@ func (a *App) startDebugServer() {
 		swagger.UIConfig(map[string]string{
 			"onComplete": fmt.Sprintf(
 				`() => {
-					window.ui.setHost('%s:%d');
-				}`, a.config.Domain, a.config.HttpPort),
+					window.ui.setHost('%s');
+				}`, a.host(a.listeners.http.Addr())),
 			"requestInterceptor": fmt.Sprintf(
 				`(req) => {
 					req.headers["X-Source"] = "%s";
@@ -193,44 +400,93 @@ func (a *App) startDebugServer() {
 	))
 	mux.HandleFunc(healthcheck.CheckHandlerPathReadiness, a.hc.ReadyEndpointHandlerFunc)
 	mux.HandleFunc(healthcheck.CheckHandlerPathLiveness, a.hc.LiveEndpointHandlerFunc)
-	mux.HandleFunc("/metrics", promhttp.Handler().ServeHTTP)
+	mux.HandleFunc(healthcheck.CheckHandlerPathStartup, a.hc.StartupEndpointHandlerFunc)
+	mux.Handle("/metrics", promhttp.InstrumentMetricHandler(
+		a.config.Registry,
+		promhttp.HandlerFor(a.config.Registry, promhttp.HandlerOpts{}),
+	))
 
 	srv := &http.Server{
-		Addr:    fmt.Sprintf(":%d", a.config.DebugPort),
 		Handler: middleware.Recovery(mux),
 	}
 
-	a.debugCloser.Add(func() error {
-		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-		defer cancel()
-
+	a.debugCloser.Add("http.debug", func(ctx context.Context) error {
 		srv.SetKeepAlivesEnabled(false)
 		if err := srv.Shutdown(ctx); err != nil {
-			return fmt.Errorf("http.debug: error during shutdown: %w", err)
+			return fmt.Errorf("error during shutdown: %w", err)
 		}
 		log.Info("http.debug: gracefully stopped")
 
 		return nil
-	})
+	}, closer.WithTimeout(time.Second))
+
+	return func() error {
+		log.Info("debug server listening on: ", a.listeners.debug.Addr().String())
+		if err := serve(srv, a.listeners.debug, a.config.DebugTLS); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("http.debug: error occurred while running server: %w", err)
+		}
+		return nil
+	}
+}
 
-	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-		log.Fatalf("http.debug: error occurred while running server: %s", err.Error())
+func serve(srv *http.Server, l net.Listener, tlsReloader *tlsconf.Reloader) error {
+	if tlsReloader != nil {
+		srv.TLSConfig = tlsReloader.ServerConfig()
+		return srv.ServeTLS(l, "", "")
 	}
+	return srv.Serve(l)
 }
 
-func (a *App) shutdown() {
+// host returns the public host of a listener for swagger and cors.
+func (a *App) host(addr net.Addr) string {
+	if port := listener.Port(addr); port != 0 {
+		return fmt.Sprintf("%s:%d", a.config.Domain, port)
+	}
+	return a.config.Domain
+}
+
+func (a *App) shutdown() error {
 	log.Info("shutdown process initiated")
+	a.setPhase(PhaseDraining)
 
 	log.Info("waiting stop of traffic")
 	time.Sleep(gracefulDelay)
+
+	// end grpc health watch streams, they would block draining
+	a.grpcHealth.Shutdown()
+
+	log.Info("draining in-flight requests: ", a.inflight.Count())
+	ctx, cancel := context.WithTimeout(context.Background(), gracefulTimeout)
+	if err := a.inflight.Wait(ctx); err != nil {
+		log.Warnf("in-flight requests not drained: %d left: %s", a.inflight.Count(), err)
+	}
+	cancel()
 	log.Info("shutting down")
 
+	var errs []error
+	ctx = context.Background()
+
 	// stop http and grpc servers
-	a.publicCloser.CloseAll()
+	errs = append(errs, a.publicCloser.CloseAll(ctx))
 
 	// stop debug server (swagger and so on)
-	a.debugCloser.CloseAll()
+	errs = append(errs, a.debugCloser.CloseAll(ctx))
+
+	// stop other resources, the global closer is closed by the process main
+	ctx, cancel = context.WithTimeout(ctx, gracefulTimeout)
+	errs = append(errs, a.closer.CloseAll(ctx))
+	cancel()
+
+	// stop background health checks
+	a.hc.Close()
 
-	// stop other resources
-	closer.CloseAll()
+	// close listeners which weren't served if shutdown came during warm-up
+	a.listeners.close()
+
+	a.setPhase(PhaseStopped)
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	return nil
 }
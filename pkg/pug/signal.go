@@ -0,0 +1,13 @@
+package pug
+
+import (
+	"context"
+	"os/signal"
+	"syscall"
+)
+
+// SignalContext returns a copy of ctx that is canceled on SIGTERM or SIGINT,
+// use it as App.Run context to shut down the app gracefully by signal.
+func SignalContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package pug
+
+import "context"
+
+// UpgradeOnSignal does nothing on this platform, there is no SIGUSR2.
+func UpgradeOnSignal(_ context.Context, _ *App) {}
@@ -0,0 +1,32 @@
+//go:build unix
+
+package pug
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// UpgradeOnSignal calls app.Upgrade on every SIGUSR2 until ctx is done.
+func UpgradeOnSignal(ctx context.Context, app *App) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGUSR2)
+
+	go func() {
+		defer signal.Stop(sig)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sig:
+				if err := app.Upgrade(ctx); err != nil {
+					log.Error(err)
+				}
+			}
+		}
+	}()
+}
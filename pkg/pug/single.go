@@ -0,0 +1,95 @@
+package pug
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/pug-go/pug-template/pkg/closer"
+	"github.com/pug-go/pug-template/pkg/listener"
+)
+
+// startSinglePortServer serves grpc and http gateway on the http port. Both
+// http/1.1 and cleartext http/2 are accepted; grpc requests are routed by
+// content type. The gateway calls grpc in-process, not over loopback.
+func (a *App) startSinglePortServer(grpcServer GrpcServer, httpServer HttpServer) func() error {
+	internalListener := listener.Pipe()
+
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+
+	srv := &http.Server{
+		Protocols: &protocols,
+	}
+
+	// http server must be stopped first: it waits for grpc requests served
+	// through it, grpc server can't drain them by itself.
+	a.publicCloser.Add("http.public", func(ctx context.Context) error {
+		if err := srv.Shutdown(ctx); err != nil {
+			return fmt.Errorf("error during shutdown: %w", err)
+		}
+		log.Info("http.public: gracefully stopped")
+
+		return nil
+	}, closer.WithPriority(priorityHttp), closer.WithTimeout(gracefulTimeout))
+	a.publicCloser.Add("grpc", func(ctx context.Context) error {
+		if err := grpcServer.Stop(ctx); err != nil {
+			return fmt.Errorf("force stopped: %w", err)
+		}
+		log.Info("grpc: gracefully stopped")
+
+		return nil
+	}, closer.WithPriority(priorityGrpc), closer.WithTimeout(gracefulTimeout))
+
+	a.useHttpMiddlewares(httpServer)
+
+	return func() error {
+		go func() {
+			if err := grpcServer.Serve(internalListener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+				log.Errorf("grpc: internal listener stopped: %s", err)
+			}
+		}()
+
+		conn, err := grpc.NewClient(
+			"passthrough:///internal",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return internalListener.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		if err != nil {
+			return fmt.Errorf("http.public: failed to dial internal grpc conn: %w", err)
+		}
+
+		gatewayHandler, err := httpServer.Handler(conn)
+		if err != nil {
+			return fmt.Errorf("http.public: %w", err)
+		}
+		srv.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isGrpcRequest(r) {
+				grpcServer.ServeHTTP(w, r)
+				return
+			}
+			gatewayHandler.ServeHTTP(w, r)
+		})
+
+		log.Info("grpc and http server listening on: ", a.listeners.http.Addr().String())
+		if err = serve(srv, a.listeners.http, a.config.HttpTLS); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("http.public: error occurred while running server: %w", err)
+		}
+		return nil
+	}
+}
+
+func isGrpcRequest(r *http.Request) bool {
+	return r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc")
+}
@@ -0,0 +1,21 @@
+//go:build !unix
+
+package pug
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// Upgrade is not supported on this platform, listeners can't be inherited
+// by a new process.
+func (a *App) Upgrade(_ context.Context) error {
+	return errors.New("upgrade: not supported on this platform")
+}
+
+func inheritedListeners() (map[string]net.Listener, error) {
+	return nil, nil
+}
+
+func notifyUpgradeReady() {}
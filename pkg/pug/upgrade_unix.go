@@ -0,0 +1,213 @@
+//go:build unix
+
+package pug
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pug-go/pug-template/pkg/listener"
+)
+
+const (
+	// envInheritedListeners lists names of listeners passed to a new process
+	// as file descriptors starting from 3, e.g. "grpc,http,debug".
+	envInheritedListeners = "PUG_INHERITED_LISTENERS"
+	// envUpgradeReadyFd is the pipe descriptor a new process writes
+	// upgradeReadyMsg to, when it serves traffic.
+	envUpgradeReadyFd = "PUG_UPGRADE_READY_FD"
+
+	upgradeReadyMsg = "ready\n"
+	upgradeTimeout  = 30 * time.Second
+)
+
+// Upgrade starts a new process of the same binary with the same arguments,
+// hands it the listeners and waits until it's ready. Then the app is shut
+// down gracefully, while the new process keeps accepting connections. If
+// the new process fails to start, it's killed and the app keeps running.
+func (a *App) Upgrade(ctx context.Context) error {
+	if a.Phase() != PhaseReady {
+		return fmt.Errorf("upgrade: %w", errNotReady)
+	}
+	if !a.upgrading.CompareAndSwap(false, true) {
+		return errors.New("upgrade: already in progress")
+	}
+	defer a.upgrading.Store(false)
+
+	names, files, err := a.listeners.files()
+	if err != nil {
+		return fmt.Errorf("upgrade: %w", err)
+	}
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("upgrade: %w", err)
+	}
+	defer readyR.Close()
+
+	exe, err := os.Executable()
+	if err != nil {
+		_ = readyW.Close()
+		return fmt.Errorf("upgrade: %w", err)
+	}
+
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = append(files, readyW)
+	cmd.Env = append(upgradeEnv(),
+		envInheritedListeners+"="+strings.Join(names, ","),
+		envUpgradeReadyFd+"="+strconv.Itoa(3+len(files)),
+	)
+
+	log.WithField("listeners", names).Info("upgrade: starting new process")
+	err = cmd.Start()
+	_ = readyW.Close()
+	if err != nil {
+		return fmt.Errorf("upgrade: failed to start new process: %w", err)
+	}
+	logger := log.WithField("pid", cmd.Process.Pid)
+
+	ready := make(chan error, 1)
+	go func() {
+		buf := make([]byte, len(upgradeReadyMsg))
+		_, err := io.ReadFull(readyR, buf)
+		ready <- err
+	}()
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, upgradeTimeout)
+	defer cancel()
+
+	select {
+	case err = <-ready:
+		if err != nil {
+			_ = cmd.Process.Kill()
+			return fmt.Errorf("upgrade: new process didn't report readiness: %w", err)
+		}
+	case err = <-exited:
+		return fmt.Errorf("upgrade: new process exited: %v", err)
+	case <-ctx.Done():
+		_ = cmd.Process.Kill()
+		return fmt.Errorf("upgrade: waiting for new process: %w", ctx.Err())
+	}
+
+	logger.Info("upgrade: new process is ready, handing over")
+	a.listeners.keepUnixSockets()
+	a.stop()
+
+	return nil
+}
+
+func (ls *listeners) files() (names []string, files []*os.File, err error) {
+	for _, nl := range ls.named() {
+		f, err := listener.File(nl.l)
+		if err != nil {
+			for _, f := range files {
+				_ = f.Close()
+			}
+			return nil, nil, fmt.Errorf("%s: %w", nl.name, err)
+		}
+		names = append(names, nl.name)
+		files = append(files, f)
+	}
+	return names, files, nil
+}
+
+// keepUnixSockets prevents removal of socket files used by the new process.
+func (ls *listeners) keepUnixSockets() {
+	for _, nl := range ls.named() {
+		if ul, ok := nl.l.(*net.UnixListener); ok {
+			ul.SetUnlinkOnClose(false)
+		}
+	}
+}
+
+type namedListener struct {
+	name string
+	l    net.Listener
+}
+
+func (ls *listeners) named() []namedListener {
+	var named []namedListener
+	for _, nl := range []namedListener{{"grpc", ls.grpc}, {"http", ls.http}, {"debug", ls.debug}} {
+		if nl.l != nil {
+			named = append(named, nl)
+		}
+	}
+	return named
+}
+
+// inheritedListeners returns listeners passed by the parent process on
+// upgrade by their names.
+func inheritedListeners() (map[string]net.Listener, error) {
+	value, ok := os.LookupEnv(envInheritedListeners)
+	if !ok {
+		return nil, nil
+	}
+	_ = os.Unsetenv(envInheritedListeners)
+
+	inherited := make(map[string]net.Listener)
+	for i, name := range strings.Split(value, ",") {
+		l, err := listener.FileListener(uintptr(3+i), "inherited "+name)
+		if err != nil {
+			return nil, err
+		}
+		inherited[name] = l
+	}
+	log.WithField("listeners", value).Info("upgrade: listeners inherited from parent process")
+
+	return inherited, nil
+}
+
+// notifyUpgradeReady tells the parent process that this one serves traffic.
+func notifyUpgradeReady() {
+	value, ok := os.LookupEnv(envUpgradeReadyFd)
+	if !ok {
+		return
+	}
+	_ = os.Unsetenv(envUpgradeReadyFd)
+
+	fd, err := strconv.Atoi(value)
+	if err != nil {
+		log.Errorf("upgrade: invalid %s: %s", envUpgradeReadyFd, value)
+		return
+	}
+
+	f := os.NewFile(uintptr(fd), "upgrade-ready")
+	defer f.Close()
+	if _, err = f.WriteString(upgradeReadyMsg); err != nil {
+		log.Errorf("upgrade: failed to notify parent process: %s", err)
+		return
+	}
+	log.WithField("ppid", os.Getppid()).Info("upgrade: parent process notified")
+}
+
+func upgradeEnv() []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, envInheritedListeners+"=") || strings.HasPrefix(kv, envUpgradeReadyFd+"=") {
+			continue
+		}
+		env = append(env, kv)
+	}
+	return env
+}
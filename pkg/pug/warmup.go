@@ -0,0 +1,46 @@
+package pug
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var errWarmingUp = errors.New("app is warming up")
+
+type warmupTask struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// AddWarmup adds a task run by Run before the public servers accept traffic,
+// e.g. filling caches or applying migrations. Tasks run one by one in the
+// order they were added, while the debug server already answers probes.
+// A failed task stops the app with its error. Must be called before Run.
+func (a *App) AddWarmup(name string, fn func(ctx context.Context) error) {
+	a.warmups = append(a.warmups, warmupTask{name: name, fn: fn})
+}
+
+func (a *App) warmup(ctx context.Context) error {
+	for _, t := range a.warmups {
+		start := time.Now()
+		log.Info("warmup: running ", t.name)
+		if err := t.fn(ctx); err != nil {
+			return fmt.Errorf("warmup: %s: %w", t.name, err)
+		}
+		log.WithField("duration", time.Since(start).String()).Info("warmup: done ", t.name)
+	}
+	return nil
+}
+
+// checkWarmup is a startup check that passes once the public servers are
+// launched after warm-up.
+func (a *App) checkWarmup(_ context.Context) error {
+	if a.Phase() == PhaseStarting {
+		return errWarmingUp
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package redact
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Placeholder replaces values of sensitive fields.
+const Placeholder = "[REDACTED]"
+
+// IsSensitive reports whether values of the field must not be logged, it's
+// marked with the debug_redact option. Nil field is not sensitive.
+func IsSensitive(fd protoreflect.FieldDescriptor) bool {
+	if fd == nil {
+		return false
+	}
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	return ok && opts.GetDebugRedact()
+}
+
+// Message replaces strings of sensitive fields with Placeholder and clears
+// other ones, nested messages are redacted too. m is modified, redact a
+// clone of messages in use.
+func Message(m protoreflect.Message) {
+	var sensitive []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if IsSensitive(fd) {
+			sensitive = append(sensitive, fd)
+			return true
+		}
+
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, item protoreflect.Value) bool {
+					Message(item.Message())
+					return true
+				})
+			}
+		case fd.IsList():
+			if fd.Message() != nil {
+				for i := 0; i < v.List().Len(); i++ {
+					Message(v.List().Get(i).Message())
+				}
+			}
+		case fd.Message() != nil:
+			Message(v.Message())
+		}
+		return true
+	})
+
+	for _, fd := range sensitive {
+		switch {
+		case fd.Kind() != protoreflect.StringKind || fd.IsMap():
+			m.Clear(fd)
+		case fd.IsList():
+			list := m.Mutable(fd).List()
+			for i := 0; i < list.Len(); i++ {
+				list.Set(i, protoreflect.ValueOfString(Placeholder))
+			}
+		default:
+			m.Set(fd, protoreflect.ValueOfString(Placeholder))
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// http headers and grpc metadata keys of the request id and the source, the
+// name of the calling service or client
+const (
+	Header         = "X-Request-Id"
+	SourceHeader   = "X-Source"
+	MetadataKey    = "x-request-id"
+	SourceMetadata = "x-source"
+)
+
+const (
+	maxIdLen     = 128
+	maxSourceLen = 64
+)
+
+type requestIdKey struct{}
+type sourceKey struct{}
+
+// New generates a random request id in the uuid v4 format.
+func New() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// Accept returns the id sent by the caller if it's valid, a new one
+// otherwise.
+func Accept(id string) string {
+	if valid(id, maxIdLen) {
+		return id
+	}
+	return New()
+}
+
+// AcceptSource returns the source sent by the caller if it's valid, empty
+// otherwise.
+func AcceptSource(source string) string {
+	if valid(source, maxSourceLen) {
+		return source
+	}
+	return ""
+}
+
+// WithRequestId returns ctx carrying the request id.
+func WithRequestId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, id)
+}
+
+// FromContext returns the request id of the request being served, empty if
+// none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIdKey{}).(string)
+	return id
+}
+
+// WithSource returns ctx carrying the source of the request.
+func WithSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, sourceKey{}, source)
+}
+
+// SourceFromContext returns the source of the request being served, empty
+// if none.
+func SourceFromContext(ctx context.Context) string {
+	source, _ := ctx.Value(sourceKey{}).(string)
+	return source
+}
+
+// Transport passes the request id and the source of the request context to
+// outbound http requests, nil base means http.DefaultTransport:
+//
+//	client := &http.Client{Transport: requestid.Transport(nil)}
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		id := FromContext(req.Context())
+		source := SourceFromContext(req.Context())
+		if id == "" && source == "" {
+			return base.RoundTrip(req)
+		}
+
+		// RoundTripper must not modify the request
+		req = req.Clone(req.Context())
+		if id != "" {
+			req.Header.Set(Header, id)
+		}
+		if source != "" {
+			req.Header.Set(SourceHeader, source)
+		}
+		return base.RoundTrip(req)
+	})
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// valid allows ids of other systems, but nothing to break logs or headers.
+func valid(value string, maxLen int) bool {
+	if value == "" || len(value) > maxLen {
+		return false
+	}
+	for _, c := range value {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':', c == '/', c == '+', c == '=':
+		default:
+			return false
+		}
+	}
+	return true
+}
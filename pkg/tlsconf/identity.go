@@ -0,0 +1,64 @@
+package tlsconf
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+)
+
+// ForwardedCertHeader is the grpc metadata key used by http gateway to pass
+// the client certificate of a http request to grpc handlers.
+const ForwardedCertHeader = "x-pug-client-cert"
+
+type identityKey struct{}
+
+// Identity of a caller authenticated by a client certificate, see
+// Reloader.PeerIdentity.
+type Identity struct {
+	// CommonName is the subject common name of the certificate.
+	CommonName string
+	// DNSNames and URIs are subject alternative names, URIs often carry
+	// SPIFFE IDs.
+	DNSNames    []string
+	URIs        []string
+	Certificate *x509.Certificate
+}
+
+func NewIdentity(cert *x509.Certificate) *Identity {
+	uris := make([]string, 0, len(cert.URIs))
+	for _, u := range cert.URIs {
+		uris = append(uris, u.String())
+	}
+
+	return &Identity{
+		CommonName:  cert.Subject.CommonName,
+		DNSNames:    cert.DNSNames,
+		URIs:        uris,
+		Certificate: cert,
+	}
+}
+
+func WithIdentity(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// IdentityFromContext returns the caller identity, ok is false if the caller
+// didn't present a verified client certificate.
+func IdentityFromContext(ctx context.Context) (id *Identity, ok bool) {
+	id, ok = ctx.Value(identityKey{}).(*Identity)
+	return id, ok
+}
+
+// EncodeCert encodes a certificate to pass it in ForwardedCertHeader.
+func EncodeCert(cert *x509.Certificate) string {
+	return base64.StdEncoding.EncodeToString(cert.Raw)
+}
+
+// DecodeCert parses a certificate passed in ForwardedCertHeader.
+func DecodeCert(value string) (*x509.Certificate, error) {
+	raw, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(raw)
+}
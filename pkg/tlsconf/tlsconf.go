@@ -0,0 +1,276 @@
+package tlsconf
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// reloadInterval is how often certificate files are checked for changes.
+const reloadInterval = 10 * time.Second
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	ClientAuth_None             = "none"
+	ClientAuth_Request          = "request"
+	ClientAuth_Require          = "require"
+	ClientAuth_VerifyIfGiven    = "verify_if_given"
+	ClientAuth_RequireAndVerify = "require_and_verify"
+)
+
+// Config describes TLS settings of a listener. TLS is disabled when CertFile
+// is empty. If ClientAuth is empty, it defaults to require_and_verify when
+// ClientCAFile is set and to none otherwise.
+type Config struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+	ClientAuth   string
+}
+
+// Reloader keeps the certificate and client CA of a listener up to date,
+// files are re-read when they change on disk.
+type Reloader struct {
+	cfg          Config
+	clientAuth   tls.ClientAuthType
+	verifyClient bool
+
+	mu        sync.Mutex
+	state     *state
+	checkedAt time.Time
+}
+
+type state struct {
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  []time.Time
+}
+
+// New loads certificates described by cfg. It returns nil if TLS is disabled.
+func New(cfg Config) (*Reloader, error) {
+	if cfg.CertFile == "" {
+		return nil, nil
+	}
+	if cfg.ClientAuth == "" {
+		cfg.ClientAuth = ClientAuth_None
+		if cfg.ClientCAFile != "" {
+			cfg.ClientAuth = ClientAuth_RequireAndVerify
+		}
+	}
+
+	r := &Reloader{cfg: cfg}
+	// client certificates are verified manually, so that the internal
+	// connection with the own certificate is always accepted
+	switch cfg.ClientAuth {
+	case ClientAuth_None:
+		r.clientAuth = tls.NoClientCert
+	case ClientAuth_Request:
+		r.clientAuth = tls.RequestClientCert
+	case ClientAuth_Require:
+		r.clientAuth = tls.RequireAnyClientCert
+	case ClientAuth_VerifyIfGiven:
+		r.clientAuth, r.verifyClient = tls.RequestClientCert, true
+	case ClientAuth_RequireAndVerify:
+		r.clientAuth, r.verifyClient = tls.RequireAnyClientCert, true
+	default:
+		return nil, fmt.Errorf("tls: unknown client auth mode %q", cfg.ClientAuth)
+	}
+	if r.verifyClient && cfg.ClientCAFile == "" {
+		return nil, fmt.Errorf("tls: client auth mode %q requires client ca file", cfg.ClientAuth)
+	}
+
+	st, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+	r.state = st
+	r.checkedAt = time.Now()
+
+	return r, nil
+}
+
+// ServerConfig returns tls config for a listener.
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.load().cert, nil
+		},
+		ClientAuth:            r.clientAuth,
+		VerifyPeerCertificate: r.verifyPeer,
+	}
+}
+
+// ClientConfig returns tls config for the internal connection to a server
+// using the same certificate, e.g. from http gateway to grpc server. The
+// own certificate is presented to the server and expected back from it.
+func (r *Reloader) ClientConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// server certificate is pinned in VerifyPeerCertificate
+		InsecureSkipVerify: true, //nolint:gosec
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return r.load().cert, nil
+		},
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 || !r.IsOwnCertificate(rawCerts[0]) {
+				return errors.New("tls: unexpected internal server certificate")
+			}
+			return nil
+		},
+	}
+}
+
+// RequestsClientCert reports whether clients are asked for certificates,
+// false for nil.
+func (r *Reloader) RequestsClientCert() bool {
+	return r != nil && r.clientAuth != tls.NoClientCert
+}
+
+// PeerIdentity returns the identity of the client certificate of the
+// connection if it was verified against the client CA in the handshake, or
+// it's the own certificate. Certificates of the request and require modes
+// aren't verified, anyone can make them, so they have no identity. Nil
+// reloader returns nil.
+func (r *Reloader) PeerIdentity(state *tls.ConnectionState) *Identity {
+	if r == nil || state == nil || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+	leaf := state.PeerCertificates[0]
+	if !r.verifyClient && !r.IsOwnCertificate(leaf.Raw) {
+		return nil
+	}
+	return NewIdentity(leaf)
+}
+
+// IsOwnCertificate reports whether raw is the DER of the current certificate.
+func (r *Reloader) IsOwnCertificate(raw []byte) bool {
+	if r == nil {
+		return false
+	}
+	return bytes.Equal(raw, r.load().cert.Certificate[0])
+}
+
+func (r *Reloader) verifyPeer(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if !r.verifyClient || len(rawCerts) == 0 || r.IsOwnCertificate(rawCerts[0]) {
+		return nil
+	}
+
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("tls: failed to parse client certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         r.load().clientCAs,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return fmt.Errorf("tls: failed to verify client certificate: %w", err)
+	}
+	return nil
+}
+
+// load returns the current state, re-reading files if they were changed.
+// On reload errors the previous state is kept.
+func (r *Reloader) load() *state {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checkedAt) < reloadInterval {
+		return r.state
+	}
+	r.checkedAt = time.Now()
+
+	modTimes, err := r.modTimes()
+	if err != nil {
+		log.Errorf("tls: failed to check certificates: %s", err)
+		return r.state
+	}
+	if equalTimes(modTimes, r.state.modTimes) {
+		return r.state
+	}
+
+	st, err := r.read()
+	if err != nil {
+		log.Errorf("tls: failed to reload certificates: %s", err)
+		return r.state
+	}
+	r.state = st
+	log.Info("tls: certificates reloaded: ", r.cfg.CertFile)
+
+	return r.state
+}
+
+func (r *Reloader) read() (*state, error) {
+	modTimes, err := r.modTimes()
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("tls: failed to load key pair: %w", err)
+	}
+
+	st := &state{cert: &cert, modTimes: modTimes}
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls: failed to read client ca: %w", err)
+		}
+		st.clientCAs = x509.NewCertPool()
+		if !st.clientCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tls: no certificates found in %s", r.cfg.ClientCAFile)
+		}
+	}
+
+	return st, nil
+}
+
+func (r *Reloader) modTimes() ([]time.Time, error) {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+
+	modTimes := make([]time.Time, 0, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes = append(modTimes, info.ModTime())
+	}
+
+	return modTimes, nil
+}
+
+func equalTimes(a, b []time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
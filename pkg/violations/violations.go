@@ -0,0 +1,128 @@
+package violations
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/pug-go/pug-template/pkg/redact"
+)
+
+// FieldPath formats the full path of a violated field: nested fields are
+// joined with dots, list items and map values are subscripted, e.g.
+// address.lines[2] or labels["env"]. The path of the request message itself
+// is empty.
+func FieldPath(path *validate.FieldPath) string {
+	var b strings.Builder
+	for _, el := range path.GetElements() {
+		if b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(el.GetFieldName())
+
+		switch s := el.GetSubscript().(type) {
+		case *validate.FieldPathElement_Index:
+			fmt.Fprintf(&b, "[%d]", s.Index)
+		case *validate.FieldPathElement_BoolKey:
+			fmt.Fprintf(&b, "[%t]", s.BoolKey)
+		case *validate.FieldPathElement_IntKey:
+			fmt.Fprintf(&b, "[%d]", s.IntKey)
+		case *validate.FieldPathElement_UintKey:
+			fmt.Fprintf(&b, "[%d]", s.UintKey)
+		case *validate.FieldPathElement_StringKey:
+			fmt.Fprintf(&b, "[%s]", strconv.Quote(s.StringKey))
+		}
+	}
+
+	return b.String()
+}
+
+// SchemaPath formats the path of a violated field without subscripts, e.g.
+// address.lines, so that it's bounded by the proto schema and safe as a
+// metric label.
+func SchemaPath(path *validate.FieldPath) string {
+	names := make([]string, 0, len(path.GetElements()))
+	for _, el := range path.GetElements() {
+		names = append(names, el.GetFieldName())
+	}
+
+	return strings.Join(names, ".")
+}
+
+// maxValueLen limits the length of values formatted by Redact.
+const maxValueLen = 64
+
+// Redact formats the violated field value for logs: sensitive fields are
+// hidden like in access logs, see redact.IsSensitive, messages, lists and
+// maps are summarized, scalars are truncated.
+func Redact(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	if redact.IsSensitive(fd) {
+		return redact.Placeholder
+	}
+
+	switch i := v.Interface().(type) {
+	case protoreflect.Message:
+		// nested fields may be redacted
+		return fmt.Sprintf("<%s>", i.Descriptor().FullName())
+	case protoreflect.List:
+		return fmt.Sprintf("<%d items>", i.Len())
+	case protoreflect.Map:
+		return fmt.Sprintf("<%d entries>", i.Len())
+	case string:
+		return strconv.Quote(truncate(i))
+	case []byte:
+		return fmt.Sprintf("<%d bytes>", len(i))
+	default:
+		return truncate(v.String())
+	}
+}
+
+func truncate(s string) string {
+	if utf8.RuneCountInString(s) <= maxValueLen {
+		return s
+	}
+	return string([]rune(s)[:maxValueLen]) + "…"
+}
+
+// RuleValue converts the rule parameter to a json value: lists stay lists,
+// durations and timestamps are formatted like in protojson. Nil is returned
+// for rules without parameter.
+func RuleValue(v protoreflect.Value) *structpb.Value {
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch i := v.Interface().(type) {
+	case protoreflect.List:
+		values := make([]*structpb.Value, 0, i.Len())
+		for n := 0; n < i.Len(); n++ {
+			if item := RuleValue(i.Get(n)); item != nil {
+				values = append(values, item)
+			}
+		}
+		return structpb.NewListValue(&structpb.ListValue{Values: values})
+	case protoreflect.Message:
+		b, err := protojson.Marshal(i.Interface())
+		if err != nil {
+			return nil
+		}
+		return structpb.NewStringValue(strings.Trim(string(b), `"`))
+	case protoreflect.EnumNumber:
+		return structpb.NewNumberValue(float64(i))
+	default:
+		value, err := structpb.NewValue(i)
+		if err != nil {
+			return structpb.NewStringValue(v.String())
+		}
+		return value
+	}
+}
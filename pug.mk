@@ -21,6 +21,9 @@ bin-deps:
 	rm -rf gen/*
 	bin/buf generate
 	mv app.swagger.json swagger.json
+	tmp=$$(mktemp -d) && bin/buf build -o $$tmp/api.binpb && \
+	go run ./cmd/pug-swagger -image $$tmp/api.binpb -swagger swagger.json && \
+	rm -rf $$tmp
 
 # Generate code from proto
 fast-generate: .generate